@@ -1,6 +1,12 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/HauKuen/Annals/internal/model"
@@ -17,8 +23,37 @@ func main() {
 	// 定期检查数据库健康状况
 	go monitorDatabaseHealth()
 
+	// 定时发布到期的文章
+	go publishScheduledArticles()
+
+	// 清除过期的置顶和推荐标记
+	go expireArticlePromotions()
+
+	// 定期将文章访问计数写入数据库
+	go flushArticleViews()
+
 	// 初始化路由并启动服务器
-	routes.InitRouter()
+	server := routes.InitRouter()
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			utils.Log.Fatal("服务器启动失败:", err)
+		}
+	}()
+
+	// 收到退出信号后停止接收新请求，等待处理中的请求完成，再写入内存中的访问计数
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	utils.Log.Info("正在关闭服务器")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		utils.Log.Error("关闭服务器失败:", err)
+	}
+	if _, err := model.FlushArticleViews(); err != nil {
+		utils.Log.Error("写入文章访问计数失败:", err)
+	}
 }
 
 func monitorDatabaseHealth() {
@@ -31,3 +66,45 @@ func monitorDatabaseHealth() {
 		}
 	}
 }
+
+func publishScheduledArticles() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := model.PublishDueArticles()
+		if err != nil {
+			utils.Log.Error("定时发布文章失败:", err)
+			continue
+		}
+		if count > 0 {
+			utils.Log.Infof("定时发布了 %d 篇文章", count)
+		}
+	}
+}
+
+func expireArticlePromotions() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := model.ExpireArticlePromotions(); err != nil {
+			utils.Log.Error("清除过期的置顶和推荐失败:", err)
+		}
+	}
+}
+
+func flushArticleViews() {
+	interval := time.Duration(utils.ViewFlushInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := model.FlushArticleViews(); err != nil {
+			utils.Log.Error("写入文章访问计数失败:", err)
+		}
+	}
+}
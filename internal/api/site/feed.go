@@ -0,0 +1,222 @@
+package site
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/feed"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// 订阅源的文件名
+const (
+	rssFile  = "rss.xml"
+	atomFile = "atom.xml"
+)
+
+// startedAt 没有文章时作为订阅源的更新时间，保证空订阅源的 ETag 稳定
+var startedAt = time.Now()
+
+// SiteFeed 全站文章的订阅源
+func SiteFeed(c *gin.Context) {
+	articles, _, code := model.GetArticles(feedQuery(), pagination.NewPage(utils.SiteFeedSize, 1))
+	if code != respcode.SUCCESS {
+		c.String(http.StatusInternalServerError, respcode.GetErrMsg(code))
+		return
+	}
+
+	writeFeed(c, &feed.Feed{
+		Title:       utils.SiteTitle,
+		Link:        utils.SiteURL,
+		Description: utils.SiteDescription,
+	}, articles)
+}
+
+// CategoryFeed 分类下文章的订阅源，包含子分类的文章
+func CategoryFeed(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.String(http.StatusNotFound, respcode.GetErrMsg(respcode.ErrorCateNotExist))
+		return
+	}
+
+	category, code := model.GetCategory(id)
+	if code != respcode.SUCCESS {
+		c.String(http.StatusNotFound, respcode.GetErrMsg(code))
+		return
+	}
+
+	articles, _, code := model.GetArticlesByCategory(id, true, feedQuery(), pagination.NewPage(utils.SiteFeedSize, 1))
+	if code != respcode.SUCCESS {
+		c.String(http.StatusInternalServerError, respcode.GetErrMsg(code))
+		return
+	}
+
+	writeFeed(c, &feed.Feed{
+		Title:       utils.SiteTitle + " - " + category.Name,
+		Link:        categoryURL(category.Slug),
+		Description: utils.SiteDescription,
+	}, articles)
+}
+
+// AuthorFeed 作者文章的订阅源
+func AuthorFeed(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.String(http.StatusNotFound, respcode.GetErrMsg(respcode.ErrorUserNotExist))
+		return
+	}
+
+	author, code := model.GetAuthorProfile(id)
+	if code != respcode.SUCCESS {
+		c.String(http.StatusNotFound, respcode.GetErrMsg(code))
+		return
+	}
+
+	articles, _, code := model.GetArticlesByUser(id, feedQuery(), pagination.NewPage(utils.SiteFeedSize, 1))
+	if code != respcode.SUCCESS {
+		c.String(http.StatusInternalServerError, respcode.GetErrMsg(code))
+		return
+	}
+
+	writeFeed(c, &feed.Feed{
+		Title:       utils.SiteTitle + " - " + authorName(author.DisplayName, author.Username),
+		Link:        authorURL(author.ID),
+		Description: author.Bio,
+	}, articles)
+}
+
+// feedQuery 订阅源按发布时间从新到旧排列，不受置顶影响
+func feedQuery() *model.ArticleQuery {
+	return &model.ArticleQuery{Sort: "published_at", IgnorePinned: true}
+}
+
+// writeFeed 按请求的文件名生成 RSS 或 Atom 订阅源，支持 ETag 和 Last-Modified 条件请求
+func writeFeed(c *gin.Context, f *feed.Feed, articles []model.Article) {
+	file := c.Param("file")
+	if file != rssFile && file != atomFile {
+		c.String(http.StatusNotFound, respcode.GetErrMsg(respcode.NotFound))
+		return
+	}
+
+	ids := make([]uint, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	contents, code := model.GetArticleContents(ids)
+	if code != respcode.SUCCESS {
+		c.String(http.StatusInternalServerError, respcode.GetErrMsg(code))
+		return
+	}
+
+	f.Self = utils.SiteURL + c.Request.URL.Path
+	f.Language = utils.SiteLanguage
+	f.Updated = startedAt
+	for i, article := range articles {
+		if i == 0 || article.UpdatedAt.After(f.Updated) {
+			f.Updated = article.UpdatedAt
+		}
+		f.Items = append(f.Items, feedItem(&article, contents[article.ID]))
+	}
+
+	var body []byte
+	var err error
+	contentType := "application/rss+xml; charset=utf-8"
+	if file == atomFile {
+		body, err = f.Atom()
+		contentType = "application/atom+xml; charset=utf-8"
+	} else {
+		body, err = f.RSS()
+	}
+	if err != nil {
+		utils.Log.Error("生成订阅源失败:", err)
+		c.String(http.StatusInternalServerError, respcode.GetErrMsg(respcode.ERROR))
+		return
+	}
+
+	writeCached(c, contentType, body, f.Updated)
+}
+
+// writeCached 写入响应并设置缓存头，ETag 为内容的哈希，客户端缓存仍然有效时返回 304
+func writeCached(c *gin.Context, contentType string, body []byte, modified time.Time) {
+	sum := sha1.Sum(body)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	modified = modified.UTC().Truncate(time.Second)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if match == etag || match == "*" {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !modified.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// feedItem 将文章转换为订阅源条目，优先使用作者填写的摘要
+//
+// content 为渲染时已过滤的正文 HTML，由 encoding/xml 转义后输出；只有公开的文章才带有正文。
+func feedItem(article *model.Article, content string) feed.Item {
+	summary := article.Summary
+	if summary == "" {
+		summary = article.Excerpt
+	}
+
+	published := article.CreatedAt
+	if article.PublishedAt != nil {
+		published = *article.PublishedAt
+	}
+
+	categories := make([]string, 0, len(article.Tags)+1)
+	if article.Category.Name != "" {
+		categories = append(categories, article.Category.Name)
+	}
+	for _, tag := range article.Tags {
+		categories = append(categories, tag.Name)
+	}
+
+	return feed.Item{
+		Title:      article.Title,
+		Link:       articleURL(article.Slug),
+		Author:     authorName(article.User.DisplayName, article.User.Username),
+		Summary:    summary,
+		Content:    content,
+		Categories: categories,
+		Published:  published,
+		Updated:    article.UpdatedAt,
+	}
+}
+
+// articleURL 文章在站点上的地址
+func articleURL(slug string) string {
+	return utils.SiteURL + "/article/" + slug
+}
+
+// categoryURL 分类在站点上的地址
+func categoryURL(slug string) string {
+	return utils.SiteURL + "/category/" + slug
+}
+
+// authorURL 作者主页在站点上的地址
+func authorURL(id uint) string {
+	return utils.SiteURL + "/user/" + strconv.FormatUint(uint64(id), 10)
+}
+
+func authorName(displayName string, username string) string {
+	if displayName != "" {
+		return displayName
+	}
+	return username
+}
@@ -0,0 +1,102 @@
+package site
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/HauKuen/Annals/internal/utils/sitemap"
+	"github.com/gin-gonic/gin"
+)
+
+// Sitemap 生成 sitemap.xml
+//
+// 页面数量超过单个 sitemap 的上限时返回 sitemap 索引，各部分通过 ?page=N 访问。
+func Sitemap(c *gin.Context) {
+	entries, err := model.GetSitemapEntries()
+	if err != nil {
+		utils.Log.Error("查询 sitemap 页面失败:", err)
+		c.String(http.StatusInternalServerError, respcode.GetErrMsg(respcode.ERROR))
+		return
+	}
+
+	urls := make([]sitemap.URL, 0, len(entries)+1)
+	home := sitemap.URL{Loc: utils.SiteURL + "/"}
+	urls = append(urls, home)
+	for _, entry := range entries {
+		urls = append(urls, sitemap.URL{Loc: sitemapLoc(entry), LastMod: entry.UpdatedAt})
+		if entry.Kind == model.SitemapArticle && entry.UpdatedAt.After(urls[0].LastMod) {
+			urls[0].LastMod = entry.UpdatedAt
+		}
+	}
+
+	pages := (len(urls) + sitemap.MaxURLs - 1) / sitemap.MaxURLs
+	page, _ := strconv.Atoi(c.Query("page"))
+
+	var body []byte
+	switch {
+	case pages <= 1 && page == 0:
+		body, err = sitemap.URLSet(urls)
+	case page == 0:
+		index := make([]sitemap.URL, pages)
+		for i := range index {
+			part := urls[i*sitemap.MaxURLs : min((i+1)*sitemap.MaxURLs, len(urls))]
+			index[i] = sitemap.URL{
+				Loc:     utils.SiteURL + "/sitemap.xml?page=" + strconv.Itoa(i+1),
+				LastMod: latest(part),
+			}
+		}
+		body, err = sitemap.Index(index)
+	case page >= 1 && page <= pages:
+		body, err = sitemap.URLSet(urls[(page-1)*sitemap.MaxURLs : min(page*sitemap.MaxURLs, len(urls))])
+	default:
+		c.String(http.StatusNotFound, respcode.GetErrMsg(respcode.NotFound))
+		return
+	}
+	if err != nil {
+		utils.Log.Error("生成 sitemap 失败:", err)
+		c.String(http.StatusInternalServerError, respcode.GetErrMsg(respcode.ERROR))
+		return
+	}
+
+	modified := latest(urls)
+	if modified.IsZero() {
+		modified = startedAt
+	}
+	writeCached(c, "application/xml; charset=utf-8", body, modified)
+}
+
+// Robots 生成 robots.txt，内容可在配置中覆盖
+func Robots(c *gin.Context) {
+	robots := utils.SiteRobots
+	if robots == "" {
+		robots = "User-agent: *\nDisallow: /api/\n\nSitemap: " + utils.SiteURL + "/sitemap.xml\n"
+	}
+	c.String(http.StatusOK, robots)
+}
+
+// sitemapLoc 页面在站点上的地址
+func sitemapLoc(entry model.SitemapEntry) string {
+	switch entry.Kind {
+	case model.SitemapArticle:
+		return articleURL(entry.Slug)
+	case model.SitemapCategory:
+		return categoryURL(entry.Slug)
+	default:
+		return authorURL(entry.ID)
+	}
+}
+
+// latest 返回页面中最新的修改时间
+func latest(urls []sitemap.URL) time.Time {
+	var t time.Time
+	for _, u := range urls {
+		if u.LastMod.After(t) {
+			t = u.LastMod
+		}
+	}
+	return t
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// RebuildSearchIndex 重建文章搜索索引
+func RebuildSearchIndex(c *gin.Context) {
+	count, code := model.RebuildSearchIndex()
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+		"data": gin.H{
+			"indexed": count,
+		},
+	})
+}
+
+// GetDashboardStats 获取管理后台统计数据
+func GetDashboardStats(c *gin.Context) {
+	data, code := model.GetDashboardStats()
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"data":    data,
+		"message": respcode.GetErrMsg(code),
+	})
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// GetArchives 获取按年、月统计的文章数量
+func GetArchives(c *gin.Context) {
+	data, code := model.GetArchives()
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"data":    data,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// GetArchiveArticles 获取某年或某月的文章
+func GetArchiveArticles(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	month := 0
+	if err == nil && c.Param("month") != "" {
+		month, err = strconv.Atoi(c.Param("month"))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetArchiveArticles(year, month, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
@@ -3,6 +3,8 @@ package v1
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/HauKuen/Annals/internal/model"
 	"github.com/HauKuen/Annals/internal/utils/respcode"
@@ -11,21 +13,104 @@ import (
 
 // GetArticles 获取文章列表
 func GetArticles(c *gin.Context) {
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
-	pageNum, _ := strconv.Atoi(c.DefaultQuery("pageNum", "1"))
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	query, ok := parseArticleQuery(c, c.GetInt("role") != 0)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetArticles(query, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
+
+// GetArticle 获取单个文章
+func GetArticle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	data, code := model.GetArticleDetail(id)
+	// 未发布的文章只对作者和管理员可见
+	if code == respcode.SUCCESS && !canViewArticle(c, &data) {
+		code = respcode.ErrorArtNotExist
+		data = model.Article{}
+	}
+	if code == respcode.SUCCESS {
+		lockArticle(c, &data)
+		recordView(c, &data)
+	}
 
-	data, total := model.GetArticles(pageSize, pageNum)
-	code := respcode.SUCCESS
 	c.JSON(http.StatusOK, gin.H{
 		"status":  code,
 		"data":    data,
-		"total":   total,
 		"message": respcode.GetErrMsg(code),
 	})
 }
 
-// GetArticle 获取单个文章
-func GetArticle(c *gin.Context) {
+// GetArticleBySlug 通过 slug 获取文章，旧 slug 会被重定向到新地址
+func GetArticleBySlug(c *gin.Context) {
+	id, current, code := model.ResolveArticleSlug(c.Param("slug"))
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+	if current != c.Param("slug") {
+		redirectToSlug(c, current)
+		return
+	}
+
+	data, code := model.GetArticleDetail(int(id))
+	if code == respcode.SUCCESS && !canViewArticle(c, &data) {
+		code = respcode.ErrorArtNotExist
+		data = model.Article{}
+	}
+	if code == respcode.SUCCESS {
+		lockArticle(c, &data)
+		recordView(c, &data)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"data":    data,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// canViewArticle 判断当前用户是否可以查看文章，未发布和私密的文章只对作者和管理员可见
+func canViewArticle(c *gin.Context, article *model.Article) bool {
+	if article.IsPublished() && article.Visibility != model.ArticleVisibilityPrivate {
+		return true
+	}
+	return isArticleOwner(c, article)
+}
+
+// isArticleOwner 当前用户是否为文章作者或管理员
+func isArticleOwner(c *gin.Context, article *model.Article) bool {
+	return c.GetInt("role") != 0 || article.UserID == c.GetUint("user_id")
+}
+
+// lockArticle 对作者和管理员以外的用户隐藏密码保护文章的正文和评论数，需通过 UnlockArticle 提交密码查看正文
+func lockArticle(c *gin.Context, article *model.Article) {
+	if article.Visibility == model.ArticleVisibilityPassword && !isArticleOwner(c, article) {
+		article.HideContent()
+		article.HideComments()
+	}
+}
+
+// UnlockArticle 提交访问密码查看密码保护的文章，请求体为 {"password": "..."}
+func UnlockArticle(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -35,7 +120,35 @@ func GetArticle(c *gin.Context) {
 		return
 	}
 
-	data, code := model.GetArticleByID(id)
+	var req struct {
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	data, code := model.GetArticleDetail(id)
+	if code == respcode.SUCCESS && !canViewArticle(c, &data) {
+		code = respcode.ErrorArtNotExist
+	}
+	if code == respcode.SUCCESS {
+		code = model.UnlockArticle(&data, req.Password, c.ClientIP())
+	}
+	if code == respcode.SUCCESS && !isArticleOwner(c, &data) {
+		data.HideComments()
+	}
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  code,
 		"data":    data,
@@ -43,6 +156,19 @@ func GetArticle(c *gin.Context) {
 	})
 }
 
+// recordView 记录已发布文章的一次访问，登录用户按用户去重，匿名访客按 IP 和 User-Agent 去重
+func recordView(c *gin.Context, article *model.Article) {
+	if !article.IsPublished() {
+		return
+	}
+
+	visitor := "ip:" + c.ClientIP() + "|" + c.Request.UserAgent()
+	if userID := c.GetUint("user_id"); userID != 0 {
+		visitor = "user:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	model.RecordView(article.ID, visitor, c.Request.UserAgent(), c.Request.Referer(), c.Request.Host)
+}
+
 // AddArticle 添加文章
 func AddArticle(c *gin.Context) {
 	var article model.Article
@@ -56,6 +182,15 @@ func AddArticle(c *gin.Context) {
 		return
 	}
 
+	// 定时发布会绕过审核，只有管理员可以在创建时设置
+	if c.GetInt("role") == 0 && (article.ScheduledAt != nil || article.Status == model.ArticleStatusScheduled) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  respcode.ErrorNoPermission,
+			"message": respcode.GetErrMsg(respcode.ErrorNoPermission),
+		})
+		return
+	}
+
 	// 从 JWT 中获取用户ID
 	userID := c.GetUint("user_id")
 	article.UserID = userID
@@ -121,7 +256,7 @@ func EditArticle(c *gin.Context) {
 		return
 	}
 
-	code = model.UpdateArticle(id, &article)
+	code = model.UpdateArticle(id, userID, &article)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  code,
 		"message": respcode.GetErrMsg(code),
@@ -165,6 +300,97 @@ func DeleteArticle(c *gin.Context) {
 	})
 }
 
+// PublishArticle 发布文章，只有管理员可以发布，作者需先提交审核
+func PublishArticle(c *gin.Context) {
+	changeArticleStatus(c, model.ArticleStatusPublished)
+}
+
+// UnpublishArticle 撤回文章为草稿
+func UnpublishArticle(c *gin.Context) {
+	changeArticleStatus(c, model.ArticleStatusDraft)
+}
+
+// SubmitArticle 提交文章审核
+func SubmitArticle(c *gin.Context) {
+	changeArticleStatus(c, model.ArticleStatusPending)
+}
+
+// ArchiveArticle 归档文章
+func ArchiveArticle(c *gin.Context) {
+	changeArticleStatus(c, model.ArticleStatusArchived)
+}
+
+// ScheduleArticle 设置文章定时发布，与直接发布一样只有管理员可以设置
+func ScheduleArticle(c *gin.Context) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.ErrorArtScheduleInvalid,
+			"message": respcode.GetErrMsg(respcode.ErrorArtScheduleInvalid),
+		})
+		return
+	}
+
+	code := model.ScheduleArticle(id, req.ScheduledAt)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// changeArticleStatus 变更文章状态
+func changeArticleStatus(c *gin.Context, status int) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	code := model.ChangeArticleStatus(id, status)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// authorizeArticle 解析文章ID并检查当前用户是否为作者或管理员，
+// 检查失败时直接写入响应并返回 false
+func authorizeArticle(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return 0, false
+	}
+
+	article, code := model.GetArticleByID(id)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return 0, false
+	}
+
+	if c.GetInt("role") == 0 && article.UserID != c.GetUint("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  respcode.ErrorNoPermission,
+			"message": respcode.GetErrMsg(respcode.ErrorNoPermission),
+		})
+		return 0, false
+	}
+
+	return id, true
+}
+
 // GetCategoryArticles 获取分类下的文章
 func GetCategoryArticles(c *gin.Context) {
 	categoryID, err := strconv.Atoi(c.Param("id"))
@@ -176,16 +402,21 @@ func GetCategoryArticles(c *gin.Context) {
 		return
 	}
 
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
-	pageNum, _ := strconv.Atoi(c.DefaultQuery("pageNum", "1"))
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
 
-	data, total, code := model.GetArticlesByCategory(categoryID, pageSize, pageNum)
-	c.JSON(http.StatusOK, gin.H{
-		"status":  code,
-		"data":    data,
-		"total":   total,
-		"message": respcode.GetErrMsg(code),
-	})
+	// descendants=true 时包含子孙分类下的文章
+	includeDescendants := c.Query("descendants") == "true"
+
+	query, ok := parseArticleQuery(c, c.GetInt("role") != 0)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetArticlesByCategory(categoryID, includeDescendants, query, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
 }
 
 // GetUserArticles 获取用户的文章
@@ -199,36 +430,200 @@ func GetUserArticles(c *gin.Context) {
 		return
 	}
 
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
-	pageNum, _ := strconv.Atoi(c.DefaultQuery("pageNum", "1"))
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	// 作者本人和管理员可以看到未发布的文章
+	includeDrafts := c.GetInt("role") != 0 || uint(userID) == c.GetUint("user_id")
 
-	data, total, code := model.GetArticlesByUser(userID, pageSize, pageNum)
+	query, ok := parseArticleQuery(c, includeDrafts)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetArticlesByUser(userID, query, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
+
+// SearchArticles 搜索文章
+func SearchArticles(c *gin.Context) {
+	keyword := c.Query("keyword")
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	query, ok := parseArticleQuery(c, c.GetInt("role") != 0)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.SearchArticles(keyword, query, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
+
+// parseArticleQuery 解析文章列表的排序和筛选参数，参数无效时直接写入响应并返回 false
+//
+// 支持的参数：sort、order、from、to（RFC3339 或 2006-01-02，to 为日期时包含当天）、
+// category、author、status；allowDrafts 表示当前用户能否查看未发布的文章。
+func parseArticleQuery(c *gin.Context, allowDrafts bool) (*model.ArticleQuery, bool) {
+	query := &model.ArticleQuery{
+		Sort:        c.Query("sort"),
+		Order:       strings.ToLower(c.Query("order")),
+		AllowDrafts: allowDrafts,
+	}
+
+	code := respcode.SUCCESS
+	if v := c.Query("from"); v != "" {
+		t, _, err := parseQueryTime(v)
+		if err != nil {
+			code = respcode.ErrorArtFilterInvalid
+		}
+		query.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, dateOnly, err := parseQueryTime(v)
+		if err != nil {
+			code = respcode.ErrorArtFilterInvalid
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		query.To = &t
+	}
+	for name, target := range map[string]*uint{"category": &query.CategoryID, "author": &query.UserID} {
+		if v := c.Query(name); v != "" {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				code = respcode.ErrorArtFilterInvalid
+			}
+			*target = uint(id)
+		}
+	}
+	if v := c.Query("status"); v != "" {
+		status, err := strconv.Atoi(v)
+		if err != nil {
+			code = respcode.ErrorArtStatusInvalid
+		}
+		query.Status = status
+	}
+
+	if code == respcode.SUCCESS {
+		code = query.Validate()
+	}
+	if code != respcode.SUCCESS {
+		httpStatus := http.StatusBadRequest
+		if code == respcode.ErrorNoPermission {
+			httpStatus = http.StatusForbidden
+		}
+		c.JSON(httpStatus, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return nil, false
+	}
+	return query, true
+}
+
+// parseQueryTime 解析 RFC3339 时间或本地日期，dateOnly 表示参数只包含日期
+func parseQueryTime(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// GetRelatedArticles 获取相关文章推荐
+func GetRelatedArticles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit <= 0 || limit > 20 {
+		limit = 5
+	}
+
+	// 未发布文章的推荐只对作者和管理员可见
+	article, code := model.GetArticleByID(id)
+	if code == respcode.SUCCESS && !canViewArticle(c, &article) {
+		code = respcode.ErrorArtNotExist
+	}
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
+	data, code := model.GetRelatedArticles(id, limit)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  code,
 		"data":    data,
-		"total":   total,
 		"message": respcode.GetErrMsg(code),
 	})
 }
 
-// SearchArticles 搜索文章
-func SearchArticles(c *gin.Context) {
-	keyword := c.Query("keyword")
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
-	pageNum, _ := strconv.Atoi(c.DefaultQuery("pageNum", "1"))
+// PinArticle 设置或取消文章置顶，请求体为 {"enabled": true, "until": "2006-01-02T15:04:05Z"}
+func PinArticle(c *gin.Context) {
+	promoteArticle(c, model.SetArticlePinned)
+}
+
+// FeatureArticle 设置或取消文章推荐，请求体同 PinArticle
+func FeatureArticle(c *gin.Context) {
+	promoteArticle(c, model.SetArticleFeatured)
+}
+
+// promoteArticle 设置文章的置顶或推荐标记，until 为空时长期有效
+func promoteArticle(c *gin.Context, set func(id int, enabled bool, until *time.Time) int) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
 
-	if pageSize <= 0 {
-		pageSize = 10
+	var req struct {
+		Enabled bool       `json:"enabled"`
+		Until   *time.Time `json:"until"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
 	}
-	if pageNum <= 0 {
-		pageNum = 1
+
+	code := set(id, req.Enabled, req.Until)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// GetFeaturedArticles 获取推荐文章
+func GetFeaturedArticles(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 50 {
+		limit = 10
 	}
 
-	data, total, code := model.SearchArticles(keyword, pageSize, pageNum)
+	data, code := model.GetFeaturedArticles(limit)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  code,
 		"data":    data,
-		"total":   total,
 		"message": respcode.GetErrMsg(code),
 	})
 }
@@ -3,6 +3,7 @@ package v1
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/HauKuen/Annals/internal/model"
 	"github.com/HauKuen/Annals/internal/utils/respcode"
@@ -50,10 +51,89 @@ func AddCategory(c *gin.Context) {
 	})
 }
 
-// DeleteCategory 删除分类
+// DeleteCategory 删除分类，分类下有文章时需通过 target 指定转移到的分类
 func DeleteCategory(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	code := model.DeleteCategory(id)
+
+	var targetID *uint
+	if target := c.Query("target"); target != "" {
+		value, err := strconv.ParseUint(target, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  respcode.BadRequest,
+				"message": respcode.GetErrMsg(respcode.BadRequest),
+			})
+			return
+		}
+		v := uint(value)
+		targetID = &v
+	}
+
+	code := model.DeleteCategory(id, targetID)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// EditCategory 修改分类名称和 slug
+func EditCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	var data model.Category
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	if data.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.ErrorEmptyCateName,
+			"message": respcode.GetErrMsg(respcode.ErrorEmptyCateName),
+		})
+		return
+	}
+
+	code := model.EditCategory(id, data.Name, data.Slug)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// MergeCategory 将分类合并到目标分类
+func MergeCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	var req struct {
+		TargetID int `json:"target_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	code := model.MergeCategory(id, req.TargetID)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  code,
 		"message": respcode.GetErrMsg(code),
@@ -74,8 +154,56 @@ func GetCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// GetCategories 获取所有分类
+// GetCategoryBySlug 通过 slug 获取分类，旧 slug 会被重定向到新地址
+func GetCategoryBySlug(c *gin.Context) {
+	id, current, code := model.ResolveCategorySlug(c.Param("slug"))
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+	if current != c.Param("slug") {
+		redirectToSlug(c, current)
+		return
+	}
+
+	data, code := model.GetCategory(int(id))
+	response := gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	}
+	if code == respcode.SUCCESS {
+		response["data"] = data
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// redirectToSlug 将请求路径中的旧 slug 替换为新 slug 并永久重定向
+func redirectToSlug(c *gin.Context, current string) {
+	path := strings.TrimSuffix(c.Request.URL.Path, c.Param("slug")) + current
+	if c.Request.URL.RawQuery != "" {
+		path += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, path)
+}
+
+// GetCategories 获取所有分类，tree=true 时返回树形结构
 func GetCategories(c *gin.Context) {
+	if c.Query("tree") == "true" {
+		data, code := model.GetCategoryTree()
+		response := gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		}
+		if code == respcode.SUCCESS {
+			response["data"] = data
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
 	data, code := model.GetCategories()
 	response := gin.H{
 		"status":  code,
@@ -86,3 +214,33 @@ func GetCategories(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// MoveCategory 修改分类的父分类
+func MoveCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	// parent_id 为 null 时移动到顶层
+	var req struct {
+		ParentID *uint `json:"parent_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	code := model.MoveCategory(id, req.ParentID)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
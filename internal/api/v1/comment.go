@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// AddComment 发表评论，请求体为 {"article_id": 1, "parent_id": 2, "content": "..."}
+func AddComment(c *gin.Context) {
+	var req struct {
+		ArticleID uint   `json:"article_id"`
+		ParentID  *uint  `json:"parent_id"`
+		Content   string `json:"content"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	// 只接收评论内容，评论者以 JWT 中的用户为准，ID、时间和审核状态由服务端决定
+	data := model.Comment{
+		ArticleID: req.ArticleID,
+		ParentID:  req.ParentID,
+		Content:   req.Content,
+		UserID:    c.GetUint("user_id"),
+		IP:        c.ClientIP(),
+	}
+
+	code := model.CreateComment(&data)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+		"data":    data,
+	})
+}
+
+// EditComment 修改评论，仅评论者本人可操作
+func EditComment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	var data struct {
+		Content string `json:"content"`
+	}
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	comment, code := model.GetComment(id)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
+	if comment.UserID != c.GetUint("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  respcode.ErrorNoPermission,
+			"message": respcode.GetErrMsg(respcode.ErrorNoPermission),
+		})
+		return
+	}
+
+	code = model.EditComment(id, data.Content)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// DeleteComment 删除评论，评论者、文章作者和管理员可操作
+func DeleteComment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	comment, code := model.GetComment(id)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if c.GetInt("role") == 0 && comment.UserID != userID {
+		article, code := model.GetArticleByID(int(comment.ArticleID))
+		if code != respcode.SUCCESS || article.UserID != userID {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  respcode.ErrorNoPermission,
+				"message": respcode.GetErrMsg(respcode.ErrorNoPermission),
+			})
+			return
+		}
+	}
+
+	code = model.DeleteComment(id)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// GetArticleComments 获取文章的评论，mode=flat 时平铺返回，默认返回树形结构
+func GetArticleComments(c *gin.Context) {
+	articleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	// 未发布文章的评论只对作者和管理员可见
+	article, code := model.GetArticleByID(articleID)
+	if code == respcode.SUCCESS && !canViewArticle(c, &article) {
+		code = respcode.ErrorArtNotExist
+	}
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+	tree := c.DefaultQuery("mode", "tree") != "flat"
+
+	// 密码保护的文章不能评论，原有的评论也只对作者和管理员可见
+	if article.Visibility == model.ArticleVisibilityPassword && !isArticleOwner(c, &article) {
+		c.JSON(http.StatusOK, listResponse(respcode.SUCCESS, []*model.Comment{}, 0, page))
+		return
+	}
+
+	data, total, code := model.GetArticleComments(articleID, tree, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
+
+// GetModerationQueue 获取待审核的评论，可通过 status 查看其他状态的评论
+func GetModerationQueue(c *gin.Context) {
+	status, _ := strconv.Atoi(c.DefaultQuery("status", strconv.Itoa(model.CommentStatusPending)))
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetModerationQueue(status, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
+
+// ApproveComments 批量通过评论
+func ApproveComments(c *gin.Context) {
+	moderateComments(c, model.CommentStatusApproved)
+}
+
+// RejectComments 批量拒绝评论
+func RejectComments(c *gin.Context) {
+	moderateComments(c, model.CommentStatusRejected)
+}
+
+// MarkCommentsSpam 批量标记为垃圾评论
+func MarkCommentsSpam(c *gin.Context) {
+	moderateComments(c, model.CommentStatusSpam)
+}
+
+// moderateComments 批量设置评论的审核状态
+func moderateComments(c *gin.Context, status int) {
+	var data struct {
+		IDs []uint `json:"ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	count, code := model.ModerateComments(data.IDs, status)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+		"data": gin.H{
+			"updated": count,
+		},
+	})
+}
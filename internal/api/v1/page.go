@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// parsePage 解析分页参数，参数无效时直接写入响应并返回 false
+//
+// 请求带有 cursor 参数时使用游标分页，cursor 为空表示第一页；否则使用 pageNum 和 pageSize 分页。
+// 游标按 (created_at, id) 分页，结果按创建时间排列；文章列表中置顶的文章只出现在第一页的最前面。
+func parsePage(c *gin.Context) (*pagination.Page, bool) {
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	cursor, ok := c.GetQuery("cursor")
+	if !ok {
+		pageNum, _ := strconv.Atoi(c.DefaultQuery("pageNum", "1"))
+		return pagination.NewPage(pageSize, pageNum), true
+	}
+
+	page, err := pagination.NewCursorPage(pageSize, cursor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.ErrorCursorInvalid,
+			"message": respcode.GetErrMsg(respcode.ErrorCursorInvalid),
+		})
+		return nil, false
+	}
+	return page, true
+}
+
+// listResponse 列表接口的响应，页码分页时带有 total，游标分页时带有 next_cursor 和 prev_cursor
+func listResponse(code int, data interface{}, total int64, page *pagination.Page) gin.H {
+	response := gin.H{
+		"status":  code,
+		"data":    data,
+		"message": respcode.GetErrMsg(code),
+	}
+	if page.UseCursor {
+		response["next_cursor"] = page.NextCursor
+		response["prev_cursor"] = page.PrevCursor
+	} else {
+		response["total"] = total
+	}
+	return response
+}
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// LikeArticle 点赞文章
+func LikeArticle(c *gin.Context) {
+	reactToArticle(c, model.LikeArticle)
+}
+
+// UnlikeArticle 取消点赞
+func UnlikeArticle(c *gin.Context) {
+	reactToArticle(c, model.UnlikeArticle)
+}
+
+// BookmarkArticle 收藏文章
+func BookmarkArticle(c *gin.Context) {
+	reactToArticle(c, model.BookmarkArticle)
+}
+
+// UnbookmarkArticle 取消收藏
+func UnbookmarkArticle(c *gin.Context) {
+	reactToArticle(c, model.UnbookmarkArticle)
+}
+
+// reactToArticle 以当前登录用户的身份对文章执行点赞或收藏操作
+func reactToArticle(c *gin.Context, action func(articleID int, userID uint) int) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	code := action(id, c.GetUint("user_id"))
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// GetMyBookmarks 获取当前登录用户收藏的文章
+func GetMyBookmarks(c *gin.Context) {
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetUserBookmarks(c.GetUint("user_id"), page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
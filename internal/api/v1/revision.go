@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/diff"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// GetArticleRevisions 获取文章的修订历史
+func GetArticleRevisions(c *gin.Context) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetArticleRevisions(id, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
+
+// GetArticleRevision 获取文章的某个修订版本
+func GetArticleRevision(c *gin.Context) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	data, code := model.GetArticleRevision(id, version)
+	response := gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	}
+	if code == respcode.SUCCESS {
+		response["data"] = data
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DiffArticleRevisions 比较文章的两个修订版本
+func DiffArticleRevisions(c *gin.Context) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	fromRevision, code := model.GetArticleRevision(id, from)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+	toRevision, code := model.GetArticleRevision(id, to)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
+	lines := diff.Lines(fromRevision.Content, toRevision.Content)
+	fromRevision.Content = ""
+	toRevision.Content = ""
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  respcode.SUCCESS,
+		"message": respcode.GetErrMsg(respcode.SUCCESS),
+		"data": gin.H{
+			"from":  fromRevision,
+			"to":    toRevision,
+			"lines": lines,
+		},
+	})
+}
+
+// RestoreArticleRevision 将文章恢复到指定修订版本
+func RestoreArticleRevision(c *gin.Context) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	code := model.RestoreArticleRevision(id, version, c.GetUint("user_id"))
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
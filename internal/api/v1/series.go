@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// AddSeries 创建系列
+func AddSeries(c *gin.Context) {
+	var data model.Series
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	data.UserID = c.GetUint("user_id")
+
+	code := model.CreateSeries(&data)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+		"data":    data,
+	})
+}
+
+// GetSeries 获取系列及其中的文章，作者本人和管理员可以看到未发布的文章
+func GetSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	data, code := model.GetSeries(id, false)
+	if code == respcode.SUCCESS && (c.GetInt("role") != 0 || data.UserID == c.GetUint("user_id")) {
+		data, code = model.GetSeries(id, true)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"data":    data,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// GetSeriesList 获取系列列表
+func GetSeriesList(c *gin.Context) {
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	data, total := model.GetSeriesList(page)
+	code := respcode.SUCCESS
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
+
+// EditSeries 修改系列
+func EditSeries(c *gin.Context) {
+	id, ok := authorizeSeries(c)
+	if !ok {
+		return
+	}
+
+	var data model.Series
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	code := model.EditSeries(id, &data)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// DeleteSeries 删除系列
+func DeleteSeries(c *gin.Context) {
+	id, ok := authorizeSeries(c)
+	if !ok {
+		return
+	}
+
+	code := model.DeleteSeries(id)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// SetSeriesArticles 设置系列中的文章及其顺序
+func SetSeriesArticles(c *gin.Context) {
+	id, ok := authorizeSeries(c)
+	if !ok {
+		return
+	}
+
+	var data struct {
+		ArticleIDs []uint `json:"article_ids"`
+	}
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	code := model.SetSeriesArticles(id, data.ArticleIDs)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// authorizeSeries 解析系列ID并检查当前用户是否为系列作者或管理员，
+// 检查失败时直接写入响应并返回 false
+func authorizeSeries(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return 0, false
+	}
+
+	series, code := model.GetSeries(id, false)
+	if code != respcode.SUCCESS {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  code,
+			"message": respcode.GetErrMsg(code),
+		})
+		return 0, false
+	}
+
+	if c.GetInt("role") == 0 && series.UserID != c.GetUint("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  respcode.ErrorNoPermission,
+			"message": respcode.GetErrMsg(respcode.ErrorNoPermission),
+		})
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// 统计接口允许查询的最大天数
+const maxStatsDays = 365
+
+// GetArticleViewTimeline 获取文章最近 days 天（默认 30 天）的每日访问量，仅作者和管理员可查看
+func GetArticleViewTimeline(c *gin.Context) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days <= 0 || days > maxStatsDays {
+		days = 30
+	}
+
+	data, code := model.GetArticleViewTimeline(id, days)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"data":    data,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// GetArticleReferrers 获取文章最近 days 天（默认 30 天）的主要来源站点，仅作者和管理员可查看
+func GetArticleReferrers(c *gin.Context) {
+	id, ok := authorizeArticle(c)
+	if !ok {
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days <= 0 || days > maxStatsDays {
+		days = 30
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	data, code := model.GetArticleReferrers(id, days, limit)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"data":    data,
+		"message": respcode.GetErrMsg(code),
+	})
+}
@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/HauKuen/Annals/internal/model"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/gin-gonic/gin"
+)
+
+// AddTag 添加标签
+func AddTag(c *gin.Context) {
+	var data model.Tag
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	data.Name = strings.TrimSpace(data.Name)
+	if data.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.ErrorEmptyTagName,
+			"message": respcode.GetErrMsg(respcode.ErrorEmptyTagName),
+		})
+		return
+	}
+
+	// 检查标签是否已存在
+	if code := model.CheckTag(data.Name); code == respcode.SUCCESS {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.ErrorTagNameUsed,
+			"message": respcode.GetErrMsg(respcode.ErrorTagNameUsed),
+		})
+		return
+	}
+
+	code := model.CreateTag(&data)
+	response := gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	}
+	if code == respcode.SUCCESS {
+		response["data"] = data
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTag 获取标签
+func GetTag(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	data, code := model.GetTag(id)
+	response := gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	}
+	if code == respcode.SUCCESS {
+		response["data"] = data
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTags 获取所有标签
+func GetTags(c *gin.Context) {
+	data, code := model.GetTags()
+	response := gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	}
+	if code == respcode.SUCCESS {
+		response["data"] = data
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// EditTag 重命名标签，标签为全站共用，仅管理员可操作
+func EditTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	var data model.Tag
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	data.Name = strings.TrimSpace(data.Name)
+	if data.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.ErrorEmptyTagName,
+			"message": respcode.GetErrMsg(respcode.ErrorEmptyTagName),
+		})
+		return
+	}
+
+	code := model.EditTag(id, data.Name)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// DeleteTag 删除标签，标签为全站共用，仅管理员可操作
+func DeleteTag(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	code := model.DeleteTag(id)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	})
+}
+
+// GetTagCloud 获取标签云
+func GetTagCloud(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+
+	data, code := model.GetTagCloud(limit)
+	response := gin.H{
+		"status":  code,
+		"message": respcode.GetErrMsg(code),
+	}
+	if code == respcode.SUCCESS {
+		response["data"] = data
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTagArticles 获取标签下的文章
+func GetTagArticles(c *gin.Context) {
+	tagID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
+	}
+
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	data, total, code := model.GetArticlesByTag(tagID, page)
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
+}
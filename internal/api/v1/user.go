@@ -46,24 +46,37 @@ func GetUserInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func GetUsers(c *gin.Context) {
-	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
-	pageNum, _ := strconv.Atoi(c.Query("pageNum"))
-	if pageSize <= 0 {
-		pageSize = 10 // 默认页面大小
-	}
-	if pageNum <= 0 {
-		pageNum = 1 // 默认页码为第一页
+// GetAuthorProfile 查询公开的作者信息
+func GetAuthorProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  respcode.BadRequest,
+			"message": respcode.GetErrMsg(respcode.BadRequest),
+		})
+		return
 	}
 
-	data, total := model.GetUsers(pageSize, pageNum)
-	code := respcode.SUCCESS
-	c.JSON(http.StatusOK, gin.H{
+	data, code := model.GetAuthorProfile(id)
+	response := gin.H{
 		"status":  code,
-		"data":    data,
-		"total":   total,
 		"message": respcode.GetErrMsg(code),
-	})
+	}
+	if code == respcode.SUCCESS {
+		response["data"] = data
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func GetUsers(c *gin.Context) {
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	data, total := model.GetUsers(page)
+	code := respcode.SUCCESS
+	c.JSON(http.StatusOK, listResponse(code, data, total, page))
 }
 
 // AddUser 添加用户
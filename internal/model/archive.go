@@ -0,0 +1,82 @@
+package model
+
+import (
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+)
+
+// archiveDate 归档使用的日期，已发布的文章按发布时间，其余按创建时间
+const archiveDate = "COALESCE(article.published_at, article.created_at)"
+
+// ArchiveYear 某一年的文章数量及按月的分布
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int64          `json:"count"`
+	Months []ArchiveMonth `json:"months"`
+}
+
+// ArchiveMonth 某一月的文章数量
+type ArchiveMonth struct {
+	Year  int   `json:"-"`
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// GetArchives 按年、月统计已发布文章的数量，从新到旧排列
+func GetArchives() ([]ArchiveYear, int) {
+	var months []ArchiveMonth
+	if err := db.Model(&Article{}).Scopes(listedScope).
+		Select("YEAR(" + archiveDate + ") AS year, MONTH(" + archiveDate + ") AS month, COUNT(*) AS count").
+		Group("year, month").
+		Order("year DESC, month DESC").
+		Scan(&months).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+
+	years := []ArchiveYear{}
+	for _, m := range months {
+		if len(years) == 0 || years[len(years)-1].Year != m.Year {
+			years = append(years, ArchiveYear{Year: m.Year})
+		}
+		y := &years[len(years)-1]
+		y.Count += m.Count
+		y.Months = append(y.Months, m)
+	}
+	return years, respcode.SUCCESS
+}
+
+// GetArchiveArticles 获取某年或某月发布的文章，month 为 0 时返回全年的文章
+func GetArchiveArticles(year int, month int, page *pagination.Page) ([]Article, int64, int) {
+	if year < 1 || year > 9999 || month < 0 || month > 12 {
+		return nil, 0, respcode.ErrorArchiveDateInvalid
+	}
+
+	var start, end time.Time
+	if month == 0 {
+		start = time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		end = start.AddDate(1, 0, 0)
+	} else {
+		start = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+		end = start.AddDate(0, 1, 0)
+	}
+
+	var articles []Article
+	var total int64
+
+	query := db.Model(&Article{}).Scopes(listedScope).
+		Where(archiveDate+" >= ? AND "+archiveDate+" < ?", start, end)
+
+	if !page.UseCursor {
+		query.Count(&total)
+	}
+	if err := query.Scopes(listColumns, withRelations, page.Scope("article.created_at", "article.id", newestFirst)).
+		Find(&articles).Error; err != nil {
+		return nil, 0, respcode.ERROR
+	}
+
+	articles = pagination.Paginate(page, articles, articleKey)
+	fillArticleExtras(articles)
+	return articles, total, respcode.SUCCESS
+}
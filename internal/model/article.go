@@ -1,43 +1,233 @@
 package model
 
 import (
+	"errors"
+	"time"
+
 	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/render"
 	"github.com/HauKuen/Annals/internal/utils/respcode"
 	"gorm.io/gorm"
 )
 
+// 文章状态
+const (
+	ArticleStatusDraft     = 1 // 草稿
+	ArticleStatusPending   = 2 // 待审核
+	ArticleStatusPublished = 3 // 已发布
+	ArticleStatusArchived  = 4 // 已归档
+	ArticleStatusScheduled = 5 // 定时发布
+)
+
+// articleStatusTransitions 允许的状态流转，发布和定时发布的接口只对管理员开放，
+// 作者只能提交审核、撤回或归档
+var articleStatusTransitions = map[int][]int{
+	ArticleStatusDraft:     {ArticleStatusPending, ArticleStatusPublished, ArticleStatusArchived, ArticleStatusScheduled},
+	ArticleStatusPending:   {ArticleStatusDraft, ArticleStatusPublished, ArticleStatusArchived, ArticleStatusScheduled},
+	ArticleStatusPublished: {ArticleStatusDraft, ArticleStatusArchived},
+	ArticleStatusArchived:  {ArticleStatusDraft, ArticleStatusPublished},
+	ArticleStatusScheduled: {ArticleStatusDraft, ArticleStatusPublished, ArticleStatusScheduled},
+}
+
 type Article struct {
 	gorm.Model
-	Title      string `gorm:"type:varchar(100);not null" json:"title"`
-	Content    string `gorm:"type:longtext;not null" json:"content"`
-	Img        string `gorm:"type:varchar(200)" json:"img"`
-	CategoryID uint   `gorm:"not null" json:"category_id"`
-	UserID     uint   `gorm:"not null" json:"user_id"`
+	Title       string     `gorm:"type:varchar(100);not null" json:"title"`
+	Slug        string     `gorm:"type:varchar(120);uniqueIndex" json:"slug"`
+	Content     string     `gorm:"type:longtext;not null" json:"content,omitempty"`
+	Format      string     `gorm:"type:varchar(20);not null;default:'markdown'" json:"format"`
+	ContentHTML string     `gorm:"type:longtext" json:"content_html,omitempty"`
+	Summary     string     `gorm:"type:varchar(500)" json:"summary"`
+	Img         string     `gorm:"type:varchar(200)" json:"img"`
+	CategoryID  uint       `gorm:"not null" json:"category_id"`
+	UserID      uint       `gorm:"not null" json:"user_id"`
+	Status      int        `gorm:"type:tinyint;not null;default:3;index" json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+	ScheduledAt *time.Time `gorm:"index" json:"scheduled_at"`
+	// 可见性，密码保护时 PasswordHash 保存加密后的访问密码
+	Visibility   int    `gorm:"type:tinyint;not null;default:1;index" json:"visibility"`
+	PasswordHash string `gorm:"type:varchar(100)" json:"-"`
+	// 访问量由内存缓冲定期写入，会略滞后于实际访问
+	ViewCount int64 `gorm:"not null;default:0" json:"view_count"`
+
+	// 置顶和推荐标记，过期时间为空表示长期有效，只能由管理员设置
+	Pinned        bool       `gorm:"not null;default:false" json:"pinned"`
+	PinnedUntil   *time.Time `json:"pinned_until"`
+	Featured      bool       `gorm:"not null;default:false;index" json:"featured"`
+	FeaturedUntil *time.Time `json:"featured_until"`
+
+	// 所属系列及在系列中的顺序，只能通过系列接口修改
+	SeriesID    *uint `gorm:"index" json:"series_id"`
+	SeriesOrder int   `gorm:"not null;default:0" json:"series_order"`
+
+	// 根据正文计算的字段，列表接口只返回摘要而不返回正文
+	Excerpt     string           `gorm:"type:varchar(500)" json:"excerpt"`
+	WordCount   int              `gorm:"not null;default:0" json:"word_count"`
+	ReadingTime int              `gorm:"not null;default:0" json:"reading_time"`
+	TOC         []render.Heading `gorm:"type:text;serializer:json" json:"toc,omitempty"`
+
+	// 搜索结果的高亮信息，仅在搜索接口中返回
+	Highlight *Highlight `gorm:"-" json:"highlight,omitempty"`
+
+	// 创建或编辑时提交的标签名，不存在的标签会自动创建
+	TagNames []string `gorm:"-" json:"tag_names,omitempty"`
+
+	// 创建或编辑时提交的访问密码，保存时加密，不会在响应中返回
+	Password string `gorm:"-" json:"password,omitempty"`
+	// 密码保护的文章未解锁时为 true，此时不返回正文
+	Locked bool `gorm:"-" json:"locked,omitempty"`
 
 	// 关联
 	Category Category `gorm:"foreignKey:CategoryID" json:"category"`
-	User     User     `gorm:"foreignKey:UserID" json:"user"`
+	User     Author   `gorm:"foreignKey:UserID" json:"user"`
+	Tags     []Tag    `gorm:"many2many:article_tag" json:"tags"`
+
+	// 分类路径，从顶层分类到文章所属分类
+	Breadcrumb []Breadcrumb `gorm:"-" json:"breadcrumb,omitempty"`
+
+	// 系列导航，仅在文章详情中返回
+	Series *SeriesNav `gorm:"-" json:"series,omitempty"`
+
+	CommentCount  int64 `gorm:"-" json:"comment_count"`
+	LikeCount     int64 `gorm:"-" json:"like_count"`
+	BookmarkCount int64 `gorm:"-" json:"bookmark_count"`
+}
+
+// IsPublished 文章是否已发布
+func (a *Article) IsPublished() bool {
+	return a.Status == ArticleStatusPublished
+}
+
+// publishedScope 只查询已发布的文章
+func publishedScope(tx *gorm.DB) *gorm.DB {
+	return tx.Where("article.status = ?", ArticleStatusPublished)
+}
+
+// backfillPublishedAt 为发布流程上线前创建的文章补全发布时间
+//
+// 这些文章迁移后默认为已发布状态，以创建时间作为发布时间，避免排序时排在最后、订阅源中缺少发布日期。
+func backfillPublishedAt() error {
+	return db.Unscoped().Model(&Article{}).
+		Where("status = ? AND published_at IS NULL", ArticleStatusPublished).
+		UpdateColumn("published_at", gorm.Expr("created_at")).Error
+}
+
+// listColumns 列表查询不加载正文和目录
+func listColumns(tx *gorm.DB) *gorm.DB {
+	return tx.Omit("content", "content_html", "toc")
+}
+
+// newestFirst 按发布时间从新到旧排序，未发布的文章排在最后
+func newestFirst(tx *gorm.DB) *gorm.DB {
+	return tx.Order("article.published_at DESC, article.id DESC")
+}
+
+// withRelations 预加载文章的分类、作者和标签
+func withRelations(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Category").Preload("User").Preload("Tags")
+}
+
+// fillArticleExtras 填充文章中不直接存储在文章表里的字段
+func fillArticleExtras(articles []Article) {
+	if len(articles) == 0 {
+		return
+	}
+
+	paths, err := categoryBreadcrumbs()
+	if err != nil {
+		utils.Log.Error("加载分类路径失败:", err)
+	} else {
+		for i := range articles {
+			articles[i].Breadcrumb = paths[articles[i].CategoryID]
+		}
+	}
+
+	ids := make([]uint, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+
+	comments, err := commentCounts(ids)
+	if err != nil {
+		utils.Log.Error("统计评论数量失败:", err)
+	} else {
+		for i := range articles {
+			articles[i].CommentCount = comments[articles[i].ID]
+		}
+	}
+
+	likes, err := reactionCounts(&ArticleLike{}, ids)
+	if err != nil {
+		utils.Log.Error("统计点赞数量失败:", err)
+	} else {
+		for i := range articles {
+			articles[i].LikeCount = likes[articles[i].ID]
+		}
+	}
+
+	bookmarks, err := reactionCounts(&ArticleBookmark{}, ids)
+	if err != nil {
+		utils.Log.Error("统计收藏数量失败:", err)
+	} else {
+		for i := range articles {
+			articles[i].BookmarkCount = bookmarks[articles[i].ID]
+		}
+	}
 }
 
-// GetArticles 获取文章列表
-func GetArticles(pageSize int, pageNum int) ([]Article, int64) {
+// GetArticles 获取文章列表，默认只返回已发布的文章，置顶的在前，其余按发布时间从新到旧
+func GetArticles(q *ArticleQuery, page *pagination.Page) ([]Article, int64, int) {
+	if code := q.validatePage(page); code != respcode.SUCCESS {
+		return nil, 0, code
+	}
+
 	var articles []Article
 	var total int64
-	offset := (pageNum - 1) * pageSize
 
-	db.Model(&Article{}).Count(&total)
-	db.Preload("Category").Preload("User").
-		Limit(pageSize).
-		Offset(offset).
-		Find(&articles)
+	if !page.UseCursor {
+		db.Model(&Article{}).Scopes(q.filter(false)).Count(&total)
+	}
+	articles, err := findArticleList(q, page, func(tx *gorm.DB) *gorm.DB { return tx })
+	if err != nil {
+		return nil, 0, respcode.ERROR
+	}
+
+	fillArticleExtras(articles)
+	return articles, total, respcode.SUCCESS
+}
+
+// findArticleList 查询置顶优先的文章列表的一页，where 为列表自身的筛选条件
+//
+// 游标分页时置顶的文章不参与 (created_at, id) 的键集排序：后续页不包含置顶的文章，
+// 第一页（包括向前翻页回到第一页）在按创建时间排列的文章之前返回全部置顶的文章。
+func findArticleList(q *ArticleQuery, page *pagination.Page, where func(*gorm.DB) *gorm.DB) ([]Article, error) {
+	pinned := !q.IgnorePinned
+	query := db.Scopes(q.filter(false), where, listColumns, withRelations)
+	if page.UseCursor && pinned {
+		query = query.Scopes(unpinnedScope)
+	}
+
+	var articles []Article
+	if err := query.Scopes(q.paginate(page, pinned, newestFirst)).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	articles = pagination.Paginate(page, articles, articleKey)
 
-	return articles, total
+	if page.UseCursor && pinned && page.IsFirst() {
+		var top []Article
+		if err := db.Scopes(q.filter(false), where, pinnedScope, listColumns, withRelations, newestFirst).
+			Find(&top).Error; err != nil {
+			return nil, err
+		}
+		articles = append(top, articles...)
+	}
+	return articles, nil
 }
 
 // GetArticleByID 获取单个文章信息
 func GetArticleByID(id int) (Article, int) {
 	var article Article
-	err := db.Preload("Category").Preload("User").First(&article, id).Error
+	err := db.Scopes(withRelations).First(&article, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return article, respcode.ErrorArtNotExist
@@ -47,6 +237,50 @@ func GetArticleByID(id int) (Article, int) {
 	return article, respcode.SUCCESS
 }
 
+// GetArticleDetail 获取文章详情，包含分类路径等附加信息
+func GetArticleDetail(id int) (Article, int) {
+	article, code := GetArticleByID(id)
+	if code != respcode.SUCCESS {
+		return article, code
+	}
+
+	articles := []Article{article}
+	fillArticleExtras(articles)
+
+	nav, err := seriesNav(&articles[0])
+	if err != nil {
+		utils.Log.Error("加载系列导航失败:", err)
+	}
+	articles[0].Series = nav
+	return articles[0], respcode.SUCCESS
+}
+
+// GetArticleContents 获取已发布的公开文章的正文 HTML，键为文章ID
+//
+// 列表查询不加载正文，供订阅源等需要全文的场景单独加载；
+// 不公开或密码保护的文章不会出现在结果中。
+func GetArticleContents(ids []uint) (map[uint]string, int) {
+	contents := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return contents, respcode.SUCCESS
+	}
+
+	var rows []struct {
+		ID          uint
+		ContentHTML string
+	}
+	if err := db.Model(&Article{}).Scopes(listedScope).
+		Select("article.id, article.content_html").
+		Where("article.id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+	for _, row := range rows {
+		contents[row.ID] = row.ContentHTML
+	}
+	return contents, respcode.SUCCESS
+}
+
 // CreateArticle 创建文章
 func CreateArticle(article *Article) int {
 	// 检查标题是否为空
@@ -59,20 +293,96 @@ func CreateArticle(article *Article) int {
 		return respcode.ErrorArtContent
 	}
 
+	// 检查内容格式并渲染正文
+	if article.Format == "" {
+		article.Format = render.FormatMarkdown
+	}
+	if !render.ValidFormat(article.Format) {
+		return respcode.ErrorArtFormatInvalid
+	}
+	if err := article.renderContent(); err != nil {
+		utils.Log.Error("渲染文章内容失败:", err)
+		return respcode.ERROR
+	}
+
+	// 新文章只能是草稿、待审核或定时发布，立即发布需走单独的接口
+	if article.ScheduledAt != nil {
+		if !article.ScheduledAt.After(time.Now()) {
+			return respcode.ErrorArtScheduleInvalid
+		}
+		article.Status = ArticleStatusScheduled
+	}
+	switch article.Status {
+	case 0:
+		article.Status = ArticleStatusDraft
+	case ArticleStatusDraft, ArticleStatusPending:
+	case ArticleStatusScheduled:
+		if article.ScheduledAt == nil {
+			return respcode.ErrorArtScheduleInvalid
+		}
+	default:
+		return respcode.ErrorArtStatusInvalid
+	}
+	article.PublishedAt = nil
+	article.ViewCount = 0
+	article.SeriesID, article.SeriesOrder = nil, 0
+	article.Pinned, article.PinnedUntil = false, nil
+	article.Featured, article.FeaturedUntil = false, nil
+	if code := article.applyVisibility(); code != respcode.SUCCESS {
+		return code
+	}
+
 	// 检查分类是否存在
 	var category Category
 	if err := db.First(&category, article.CategoryID).Error; err != nil {
 		return respcode.ErrorCateNotExist
 	}
 
-	// 创建文章
-	if err := db.Create(article).Error; err != nil {
-		utils.Log.Error("创建文章失败:", err)
-		return respcode.ERROR
+	// 作者可以自定义 slug，否则根据标题生成
+	if article.Slug != "" {
+		if code := checkSlug(SlugKindArticle, article.Slug, 0); code != respcode.SUCCESS {
+			return code
+		}
+	}
+
+	// 标签只能通过 TagNames 设置
+	article.Tags = nil
+
+	// 创建文章并保存初始修订版本
+	code := respcode.SUCCESS
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 在事务中加锁确认分类仍然存在，与删除分类互斥
+		if err := lockCategory(tx, article.CategoryID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				code = respcode.ErrorCateNotExist
+			}
+			return err
+		}
+		if article.Slug == "" {
+			s, err := uniqueSlug(tx, SlugKindArticle, makeSlug(article.Title), 0)
+			if err != nil {
+				return err
+			}
+			article.Slug = s
+		}
+		if err := tx.Create(article).Error; err != nil {
+			return err
+		}
+		if err := setArticleTags(tx, article, article.TagNames); err != nil {
+			return err
+		}
+		return saveRevision(tx, article, article.UserID)
+	})
+	if err != nil {
+		if code == respcode.SUCCESS {
+			utils.Log.Error("创建文章失败:", err)
+			code = respcode.ERROR
+		}
+		return code
 	}
 
 	// 加载关联的分类和用户信息
-	if err := db.Preload("Category").Preload("User").First(article, article.ID).Error; err != nil {
+	if err := db.Scopes(withRelations).First(article, article.ID).Error; err != nil {
 		utils.Log.Error("加载文章关联信息失败:", err)
 		return respcode.ERROR
 	}
@@ -80,8 +390,8 @@ func CreateArticle(article *Article) int {
 	return respcode.SUCCESS
 }
 
-// UpdateArticle 更新文章
-func UpdateArticle(id int, article *Article) int {
+// UpdateArticle 更新文章，每次更新都会保存一个修订版本
+func UpdateArticle(id int, editorID uint, article *Article) int {
 	var existingArticle Article
 
 	// 检查文章是否存在
@@ -108,14 +418,29 @@ func UpdateArticle(id int, article *Article) int {
 	if article.Content != "" {
 		updates["content"] = article.Content
 	}
+	if article.Format != "" {
+		if !render.ValidFormat(article.Format) {
+			return respcode.ErrorArtFormatInvalid
+		}
+		updates["format"] = article.Format
+	}
+	if article.Summary != "" {
+		updates["summary"] = article.Summary
+	}
 	if article.CategoryID != 0 {
 		updates["category_id"] = article.CategoryID
 	}
-
-	if err := db.Model(&existingArticle).Updates(updates).Error; err != nil {
-		return respcode.ERROR
+	if article.Slug != "" && article.Slug != existingArticle.Slug {
+		if code := checkSlug(SlugKindArticle, article.Slug, existingArticle.ID); code != respcode.SUCCESS {
+			return code
+		}
+		updates["slug"] = article.Slug
 	}
-	return respcode.SUCCESS
+	if code := visibilityUpdates(&existingArticle, article, updates); code != respcode.SUCCESS {
+		return code
+	}
+
+	return applyArticleUpdate(id, editorID, updates, article.TagNames)
 }
 
 // DeleteArticle 删除文章
@@ -131,14 +456,124 @@ func DeleteArticle(id int) int {
 	if err := db.Delete(&article).Error; err != nil {
 		return respcode.ERROR
 	}
+
+	articleChanged(article.ID)
+	return respcode.SUCCESS
+}
+
+// ChangeArticleStatus 变更文章状态
+func ChangeArticleStatus(id int, status int) int {
+	var article Article
+	if err := db.First(&article, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return respcode.ErrorArtNotExist
+		}
+		return respcode.ERROR
+	}
+
+	if status == ArticleStatusScheduled {
+		// 定时发布需要指定发布时间，请使用 ScheduleArticle
+		return respcode.ErrorArtScheduleInvalid
+	}
+	if !canTransition(article.Status, status) {
+		return respcode.ErrorArtStatusTransition
+	}
+
+	updates := map[string]interface{}{"status": status}
+	// 首次发布时记录发布时间
+	if status == ArticleStatusPublished && article.PublishedAt == nil {
+		updates["published_at"] = time.Now()
+	}
+	// 离开定时发布状态时取消计划
+	if article.Status == ArticleStatusScheduled {
+		updates["scheduled_at"] = nil
+	}
+
+	if err := db.Model(&article).Updates(updates).Error; err != nil {
+		utils.Log.Error("更新文章状态失败:", err)
+		return respcode.ERROR
+	}
+
+	articleChanged(article.ID)
 	return respcode.SUCCESS
 }
 
-// GetArticlesByCategory 获取分类下的文章
-func GetArticlesByCategory(categoryID int, pageSize int, pageNum int) ([]Article, int64, int) {
+// ScheduleArticle 设置文章在指定时间自动发布
+func ScheduleArticle(id int, publishAt time.Time) int {
+	if !publishAt.After(time.Now()) {
+		return respcode.ErrorArtScheduleInvalid
+	}
+
+	var article Article
+	if err := db.First(&article, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return respcode.ErrorArtNotExist
+		}
+		return respcode.ERROR
+	}
+
+	if !canTransition(article.Status, ArticleStatusScheduled) {
+		return respcode.ErrorArtStatusTransition
+	}
+
+	if err := db.Model(&article).Updates(map[string]interface{}{
+		"status":       ArticleStatusScheduled,
+		"scheduled_at": publishAt,
+	}).Error; err != nil {
+		utils.Log.Error("设置定时发布失败:", err)
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// PublishDueArticles 发布所有已到计划时间的文章，返回发布的数量
+//
+// 使用单条带条件的 UPDATE 完成状态切换，多个实例同时执行时
+// 每篇文章只会被其中一个实例发布一次。
+func PublishDueArticles() (int64, error) {
+	var ids []uint
+	if err := db.Model(&Article{}).
+		Where("status = ? AND scheduled_at <= ?", ArticleStatusScheduled, time.Now()).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// 再次带上状态条件，已被其他实例发布的文章不会被重复更新
+	result := db.Model(&Article{}).
+		Where("id IN ? AND status = ?", ids, ArticleStatusScheduled).
+		Updates(map[string]interface{}{
+			"status":       ArticleStatusPublished,
+			"published_at": gorm.Expr("COALESCE(published_at, scheduled_at)"),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	articleChanged(ids...)
+	return result.RowsAffected, nil
+}
+
+// canTransition 判断文章状态能否从 from 流转到 to
+func canTransition(from int, to int) bool {
+	for _, next := range articleStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GetArticlesByCategory 获取分类下的文章，includeDescendants 为 true 时包含所有子孙分类的文章
+func GetArticlesByCategory(categoryID int, includeDescendants bool, q *ArticleQuery, page *pagination.Page) ([]Article, int64, int) {
+	if code := q.validatePage(page); code != respcode.SUCCESS {
+		return nil, 0, code
+	}
+
 	var articles []Article
 	var total int64
-	offset := (pageNum - 1) * pageSize
 
 	// 检查分类是否存在
 	var category Category
@@ -146,23 +581,37 @@ func GetArticlesByCategory(categoryID int, pageSize int, pageNum int) ([]Article
 		return nil, 0, respcode.ErrorCateNotExist
 	}
 
-	db.Model(&Article{}).Where("category_id = ?", categoryID).Count(&total)
-	if err := db.Preload("Category").Preload("User").
-		Where("category_id = ?", categoryID).
-		Limit(pageSize).
-		Offset(offset).
-		Find(&articles).Error; err != nil {
+	categoryIDs := []uint{category.ID}
+	if includeDescendants {
+		ids, err := categoryDescendantIDs(db, category.ID)
+		if err != nil {
+			return nil, 0, respcode.ERROR
+		}
+		categoryIDs = ids
+	}
+
+	if !page.UseCursor {
+		db.Model(&Article{}).Scopes(q.filter(false)).Where("article.category_id IN ?", categoryIDs).Count(&total)
+	}
+	articles, err := findArticleList(q, page, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("article.category_id IN ?", categoryIDs)
+	})
+	if err != nil {
 		return nil, 0, respcode.ERROR
 	}
 
+	fillArticleExtras(articles)
 	return articles, total, respcode.SUCCESS
 }
 
-// GetArticlesByUser 获取用户的文章
-func GetArticlesByUser(userID int, pageSize int, pageNum int) ([]Article, int64, int) {
+// GetArticlesByUser 获取用户的文章，q.AllowDrafts 为 true 时默认包含未发布的文章
+func GetArticlesByUser(userID int, q *ArticleQuery, page *pagination.Page) ([]Article, int64, int) {
+	if code := q.validatePage(page); code != respcode.SUCCESS {
+		return nil, 0, code
+	}
+
 	var articles []Article
 	var total int64
-	offset := (pageNum - 1) * pageSize
 
 	// 检查用户是否存在
 	var user User
@@ -170,43 +619,17 @@ func GetArticlesByUser(userID int, pageSize int, pageNum int) ([]Article, int64,
 		return nil, 0, respcode.ErrorUserNotExist
 	}
 
-	db.Model(&Article{}).Where("user_id = ?", userID).Count(&total)
-	if err := db.Preload("Category").Preload("User").
-		Where("user_id = ?", userID).
-		Limit(pageSize).
-		Offset(offset).
-		Find(&articles).Error; err != nil {
-		return nil, 0, respcode.ERROR
-	}
+	query := db.Model(&Article{}).Scopes(q.filter(true)).Where("article.user_id = ?", userID)
 
-	return articles, total, respcode.SUCCESS
-}
-
-// SearchArticles 搜索文章
-func SearchArticles(keyword string, pageSize int, pageNum int) ([]Article, int64, int) {
-	var articles []Article
-	var total int64
-	offset := (pageNum - 1) * pageSize
-
-	if keyword == "" {
-		return nil, 0, respcode.BadRequest
+	if !page.UseCursor {
+		query.Count(&total)
 	}
-
-	// 使用 LIKE 进行模糊搜索标题
-	query := db.Model(&Article{}).Where("title LIKE ?", "%"+keyword+"%")
-
-	// 获取总数
-	query.Count(&total)
-
-	// 获取分页数据
-	err := query.Preload("Category").Preload("User").
-		Limit(pageSize).
-		Offset(offset).
-		Find(&articles).Error
-
-	if err != nil {
+	if err := query.Scopes(listColumns, withRelations, q.paginate(page, false, newestFirst)).
+		Find(&articles).Error; err != nil {
 		return nil, 0, respcode.ERROR
 	}
 
+	articles = pagination.Paginate(page, articles, articleKey)
+	fillArticleExtras(articles)
 	return articles, total, respcode.SUCCESS
 }
@@ -1,19 +1,53 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/HauKuen/Annals/internal/utils"
 	"github.com/HauKuen/Annals/internal/utils/respcode"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Category struct {
 	gorm.Model
-	Name string `gorm:"type:varchar(100);uniqueIndex;not null" json:"name" validate:"required"`
+	Name     string `gorm:"type:varchar(100);uniqueIndex;not null" json:"name" validate:"required"`
+	Slug     string `gorm:"type:varchar(120);uniqueIndex" json:"slug"`
+	ParentID *uint  `gorm:"index" json:"parent_id"`
+
+	// 树形结构中的子分类
+	Children []*Category `gorm:"-" json:"children,omitempty"`
+}
+
+// Breadcrumb 分类路径中的一级
+type Breadcrumb struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
 }
 
 // CreateCategory 创建分类
 func CreateCategory(data *Category) int {
+	if data.ParentID != nil {
+		var parent Category
+		if err := db.Select("id").First(&parent, *data.ParentID).Error; err != nil {
+			return respcode.ErrorCateParentNotExist
+		}
+	}
+
+	if data.Slug != "" {
+		if code := checkSlug(SlugKindCategory, data.Slug, 0); code != respcode.SUCCESS {
+			return code
+		}
+	} else {
+		s, err := uniqueSlug(db, SlugKindCategory, makeSlug(data.Name), 0)
+		if err != nil {
+			return respcode.ERROR
+		}
+		data.Slug = s
+	}
+
 	if err := db.Create(data).Error; err != nil {
 		fmt.Print(err)
 		return respcode.ERROR
@@ -31,19 +65,173 @@ func CheckCategory(name string) int {
 }
 
 // DeleteCategory 删除分类
-func DeleteCategory(id int) int {
+//
+// 分类下仍有文章时，若未指定 targetID 则拒绝删除，否则先将文章转移到目标分类。
+// 子分类会被挂到目标分类下，未指定目标分类时挂到被删除分类的父分类下。
+func DeleteCategory(id int, targetID *uint) int {
 	var category Category
 	// 查询分类是否存在
-	if err := db.Select("id").Where("id = ?", id).First(&category).Error; err != nil {
+	if err := db.Where("id = ?", id).First(&category).Error; err != nil {
 		return respcode.ErrorCateNotExist
 	}
 
-	if err := db.Where("id = ?", id).Delete(&Category{}).Error; err != nil {
+	if targetID != nil {
+		return MergeCategory(id, int(*targetID))
+	}
+
+	code := respcode.SUCCESS
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 锁定分类行，并以加锁读统计文章，阻止删除过程中向该分类添加文章
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				code = respcode.ErrorCateNotExist
+			}
+			return err
+		}
+
+		// 包括已删除的文章，避免恢复后指向不存在的分类
+		var count int64
+		if err := tx.Unscoped().Model(&Article{}).Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("category_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			code = respcode.ErrorCateHasArticles
+			return errors.New("category has articles")
+		}
+
+		if err := tx.Model(&Category{}).Where("parent_id = ?", id).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	})
+	if err != nil {
+		if code == respcode.SUCCESS {
+			utils.Log.Error("删除分类失败:", err)
+			code = respcode.ERROR
+		}
+		return code
+	}
+	return respcode.SUCCESS
+}
+
+// lockCategory 以共享锁读取分类，分类不存在或已删除时返回 gorm.ErrRecordNotFound
+//
+// 向分类添加文章前调用，DeleteCategory 持有分类的排他锁时会等待其完成。
+func lockCategory(tx *gorm.DB, id uint) error {
+	return tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").First(&Category{}, id).Error
+}
+
+// EditCategory 修改分类名称和 slug，slug 为空时保持不变
+func EditCategory(id int, name string, newSlug string) int {
+	var category Category
+	if err := db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return respcode.ErrorCateNotExist
+		}
+		return respcode.ERROR
+	}
+
+	// 已删除的分类仍占用唯一索引
+	var count int64
+	db.Unscoped().Model(&Category{}).Where("name = ? AND id != ?", name, id).Count(&count)
+	if count > 0 {
+		return respcode.ErrorCateNameUsed
+	}
+
+	updates := map[string]interface{}{"name": name}
+	if newSlug != "" && newSlug != category.Slug {
+		if code := checkSlug(SlugKindCategory, newSlug, category.ID); code != respcode.SUCCESS {
+			return code
+		}
+		updates["slug"] = newSlug
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if _, ok := updates["slug"]; ok {
+			if err := recordSlugChange(tx, SlugKindCategory, category.ID, category.Slug, newSlug); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&category).Updates(updates).Error
+	})
+	if err != nil {
+		utils.Log.Error("修改分类失败:", err)
 		return respcode.ERROR
 	}
 	return respcode.SUCCESS
 }
 
+// MergeCategory 将源分类合并到目标分类：文章和子分类转移到目标分类后删除源分类
+//
+// 源分类加排他锁、目标分类加共享锁后再转移文章，并发添加到源分类的文章会等待合并完成后失败；
+// 与 MoveCategory 一样锁定所有分类的父分类关系后检查环。
+func MergeCategory(sourceID int, targetID int) int {
+	if sourceID == targetID {
+		return respcode.ErrorCateMergeSelf
+	}
+
+	code := respcode.SUCCESS
+	var moved []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var source Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&source, sourceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				code = respcode.ErrorCateNotExist
+			}
+			return err
+		}
+		var target Category
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&target, targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				code = respcode.ErrorCateNotExist
+			}
+			return err
+		}
+
+		// 目标分类不能是源分类的子孙分类，否则子分类转移后会形成环
+		parents, err := loadCategoryParents(tx.Clauses(clause.Locking{Strength: "UPDATE"}))
+		if err != nil {
+			return err
+		}
+		visited := make(map[uint]bool)
+		for current := &target.ID; current != nil && !visited[*current]; current = parents[*current] {
+			if *current == source.ID {
+				code = respcode.ErrorCateCycle
+				return errors.New("category cycle")
+			}
+			visited[*current] = true
+		}
+
+		// 包括已删除的文章，避免恢复后指向不存在的分类；加锁读取以包含锁定前已提交的文章
+		if err := tx.Unscoped().Model(&Article{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("category_id = ?", source.ID).Pluck("id", &moved).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&Article{}).Where("category_id = ?", source.ID).
+			Update("category_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&Category{}).Where("parent_id = ?", source.ID).
+			Update("parent_id", target.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&source).Error
+	})
+	if err != nil {
+		if code == respcode.SUCCESS {
+			utils.Log.Error("合并分类失败:", err)
+			code = respcode.ERROR
+		}
+		return code
+	}
+
+	// 文章的分类变化会影响搜索索引和相关文章的评分
+	articleChanged(moved...)
+	return respcode.SUCCESS
+}
+
 // GetCategory 查询分类
 func GetCategory(id int) (Category, int) {
 	var category Category
@@ -63,3 +251,150 @@ func GetCategories() ([]Category, int) {
 
 	return categories, respcode.SUCCESS
 }
+
+// GetCategoryTree 获取分类树
+func GetCategoryTree() ([]*Category, int) {
+	var categories []Category
+	if err := db.Order("id").Find(&categories).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+	return buildCategoryTree(categories), respcode.SUCCESS
+}
+
+// MoveCategory 修改分类的父分类，parentID 为 nil 时移动到顶层
+//
+// 检查和更新在同一事务中进行，并锁定所有分类的父分类关系，避免并发移动形成环。
+func MoveCategory(id int, parentID *uint) int {
+	code := respcode.SUCCESS
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var category Category
+		if err := tx.First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				code = respcode.ErrorCateNotExist
+			}
+			return err
+		}
+
+		if parentID != nil {
+			parents, err := loadCategoryParents(tx.Clauses(clause.Locking{Strength: "UPDATE"}))
+			if err != nil {
+				return err
+			}
+			if _, ok := parents[*parentID]; !ok {
+				code = respcode.ErrorCateParentNotExist
+				return errors.New("parent category not found")
+			}
+			// 新的父分类不能是自身或自身的子孙分类
+			visited := make(map[uint]bool)
+			for current := parentID; current != nil && !visited[*current]; current = parents[*current] {
+				if *current == category.ID {
+					code = respcode.ErrorCateCycle
+					return errors.New("category cycle")
+				}
+				visited[*current] = true
+			}
+		}
+
+		return tx.Model(&category).Update("parent_id", parentID).Error
+	})
+	if err != nil {
+		if code == respcode.SUCCESS {
+			utils.Log.Error("移动分类失败:", err)
+			code = respcode.ERROR
+		}
+		return code
+	}
+	return respcode.SUCCESS
+}
+
+// buildCategoryTree 将分类列表组装为树，父分类不存在的分类视为顶层分类
+func buildCategoryTree(categories []Category) []*Category {
+	nodes := make(map[uint]*Category, len(categories))
+	for i := range categories {
+		nodes[categories[i].ID] = &categories[i]
+	}
+
+	var roots []*Category
+	for i := range categories {
+		node := &categories[i]
+		if node.ParentID != nil {
+			if parent, ok := nodes[*node.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// loadCategoryParents 加载所有分类的父分类映射
+func loadCategoryParents(tx *gorm.DB) (map[uint]*uint, error) {
+	var categories []Category
+	if err := tx.Select("id", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	parents := make(map[uint]*uint, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+	return parents, nil
+}
+
+// categoryDescendantIDs 获取分类自身及其所有子孙分类的ID
+func categoryDescendantIDs(tx *gorm.DB, id uint) ([]uint, error) {
+	parents, err := loadCategoryParents(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]uint)
+	for childID, parentID := range parents {
+		if parentID != nil {
+			children[*parentID] = append(children[*parentID], childID)
+		}
+	}
+
+	ids := []uint{id}
+	visited := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, childID := range children[ids[i]] {
+			if !visited[childID] {
+				visited[childID] = true
+				ids = append(ids, childID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// categoryBreadcrumbs 计算每个分类从顶层到自身的路径
+func categoryBreadcrumbs() (map[uint][]Breadcrumb, error) {
+	var categories []Category
+	if err := db.Select("id", "name", "slug", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	paths := make(map[uint][]Breadcrumb, len(categories))
+	for _, category := range categories {
+		var path []Breadcrumb
+		visited := make(map[uint]bool)
+		current, ok := category, true
+		for ok && !visited[current.ID] {
+			visited[current.ID] = true
+			path = append([]Breadcrumb{{ID: current.ID, Name: current.Name, Slug: current.Slug}}, path...)
+			if current.ParentID == nil {
+				break
+			}
+			current, ok = byID[*current.ParentID]
+		}
+		paths[category.ID] = path
+	}
+	return paths, nil
+}
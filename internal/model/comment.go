@@ -0,0 +1,317 @@
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+)
+
+// 评论审核状态
+const (
+	CommentStatusPending  = 1 // 待审核
+	CommentStatusApproved = 2 // 已通过
+	CommentStatusSpam     = 3 // 垃圾评论
+	CommentStatusRejected = 4 // 已拒绝
+)
+
+type Comment struct {
+	gorm.Model
+	ArticleID uint   `gorm:"not null;index" json:"article_id"`
+	UserID    uint   `gorm:"not null;index" json:"user_id"`
+	ParentID  *uint  `gorm:"index" json:"parent_id"`
+	Content   string `gorm:"type:text;not null" json:"content"`
+	// 新增字段前已存在的评论视为已通过
+	Status int    `gorm:"type:tinyint;not null;default:2;index" json:"status"`
+	IP     string `gorm:"type:varchar(45);index" json:"-"`
+
+	User Author `gorm:"foreignKey:UserID" json:"user"`
+
+	// 树形结构中的回复
+	Replies []*Comment `gorm:"-" json:"replies,omitempty"`
+}
+
+// CreateComment 发表评论，ParentID 不为空时为回复
+func CreateComment(comment *Comment) int {
+	comment.Content = strings.TrimSpace(comment.Content)
+	if comment.Content == "" {
+		return respcode.ErrorCommentEmpty
+	}
+
+	// 只能评论已发布的文章
+	var article Article
+	if err := db.Scopes(accessibleScope).Select("id").First(&article, comment.ArticleID).Error; err != nil {
+		return respcode.ErrorArtNotExist
+	}
+
+	// 只能回复同一篇文章下已通过审核的评论
+	if comment.ParentID != nil {
+		var parent Comment
+		err := db.Select("id").
+			Where("article_id = ? AND status = ?", comment.ArticleID, CommentStatusApproved).
+			First(&parent, *comment.ParentID).Error
+		if err != nil {
+			return respcode.ErrorCommentParentInvalid
+		}
+	}
+
+	status, code := moderateComment(comment, false)
+	if code != respcode.SUCCESS {
+		return code
+	}
+	comment.Status = status
+
+	if err := db.Create(comment).Error; err != nil {
+		utils.Log.Error("发表评论失败:", err)
+		return respcode.ERROR
+	}
+
+	if err := db.Preload("User").First(comment, comment.ID).Error; err != nil {
+		utils.Log.Error("加载评论失败:", err)
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// GetComment 查询评论
+func GetComment(id int) (Comment, int) {
+	var comment Comment
+	if err := db.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return comment, respcode.ErrorCommentNotExist
+		}
+		return comment, respcode.ERROR
+	}
+	return comment, respcode.SUCCESS
+}
+
+// EditComment 修改评论内容，已通过和待审核的评论修改后重新审核
+//
+// 已拒绝和垃圾评论修改后保持原状态，只能由管理员改变。
+func EditComment(id int, content string) int {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return respcode.ErrorCommentEmpty
+	}
+
+	comment, code := GetComment(id)
+	if code != respcode.SUCCESS {
+		return code
+	}
+
+	comment.Content = content
+	updates := map[string]interface{}{"content": content}
+	if comment.Status == CommentStatusApproved || comment.Status == CommentStatusPending {
+		status, code := moderateComment(&comment, true)
+		if code != respcode.SUCCESS {
+			return code
+		}
+		updates["status"] = status
+	}
+
+	if err := db.Model(&comment).Updates(updates).Error; err != nil {
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// moderateComment 根据垃圾评论检测结果和审核配置决定评论的状态，edit 为 true 时表示修改已有的评论
+func moderateComment(comment *Comment, edit bool) (int, int) {
+	switch checkSpam(comment, edit) {
+	case SpamBlock:
+		return 0, respcode.ErrorCommentRejected
+	case SpamSpam:
+		return CommentStatusSpam, respcode.SUCCESS
+	}
+
+	if !utils.CommentModeration {
+		return CommentStatusApproved, respcode.SUCCESS
+	}
+
+	trusted, err := trustedCommenter(comment.UserID)
+	if err != nil {
+		utils.Log.Error("查询评论者信息失败:", err)
+		return 0, respcode.ERROR
+	}
+	if trusted {
+		return CommentStatusApproved, respcode.SUCCESS
+	}
+	return CommentStatusPending, respcode.SUCCESS
+}
+
+// trustedCommenter 管理员和已有足够多评论通过审核的用户免审
+func trustedCommenter(userID uint) (bool, error) {
+	var user User
+	if err := db.Select("id", "role").First(&user, userID).Error; err != nil {
+		return false, err
+	}
+	if user.Role != 0 {
+		return true, nil
+	}
+	if utils.CommentTrustedApproved <= 0 {
+		return false, nil
+	}
+
+	var approved int64
+	if err := db.Model(&Comment{}).
+		Where("user_id = ? AND status = ?", userID, CommentStatusApproved).
+		Count(&approved).Error; err != nil {
+		return false, err
+	}
+	return approved >= int64(utils.CommentTrustedApproved), nil
+}
+
+// DeleteComment 删除评论及其所有回复
+func DeleteComment(id int) int {
+	comment, code := GetComment(id)
+	if code != respcode.SUCCESS {
+		return code
+	}
+
+	var comments []Comment
+	if err := db.Select("id", "parent_id").Where("article_id = ?", comment.ArticleID).Find(&comments).Error; err != nil {
+		return respcode.ERROR
+	}
+
+	children := make(map[uint][]uint)
+	for _, c := range comments {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+	ids := []uint{comment.ID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+
+	if err := db.Where("id IN ?", ids).Delete(&Comment{}).Error; err != nil {
+		utils.Log.Error("删除评论失败:", err)
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// GetArticleComments 获取文章已通过审核的评论
+//
+// tree 为 true 时按顶层评论分页，每条顶层评论带有完整的回复树；
+// 否则按发表时间平铺分页。
+func GetArticleComments(articleID int, tree bool, page *pagination.Page) ([]*Comment, int64, int) {
+	var comments []*Comment
+	var total int64
+
+	query := db.Model(&Comment{}).Where("article_id = ? AND status = ?", articleID, CommentStatusApproved)
+	if tree {
+		query = query.Where("parent_id IS NULL")
+	}
+
+	if !page.UseCursor {
+		query.Count(&total)
+	}
+	page.Asc = true
+	if err := query.Preload("User").
+		Scopes(page.Scope("comment.created_at", "comment.id", oldestFirst)).
+		Find(&comments).Error; err != nil {
+		return nil, 0, respcode.ERROR
+	}
+	comments = pagination.Paginate(page, comments, commentKey)
+
+	if !tree || len(comments) == 0 {
+		return comments, total, respcode.SUCCESS
+	}
+
+	// 加载文章的全部回复，挂到当前页的顶层评论下；未通过审核的评论连同其回复一起隐藏
+	var replies []*Comment
+	if err := db.Preload("User").
+		Where("article_id = ? AND parent_id IS NOT NULL AND status = ?", articleID, CommentStatusApproved).
+		Order("created_at, id").
+		Find(&replies).Error; err != nil {
+		return nil, 0, respcode.ERROR
+	}
+
+	nodes := make(map[uint]*Comment, len(comments)+len(replies))
+	for _, c := range comments {
+		nodes[c.ID] = c
+	}
+	for _, c := range replies {
+		nodes[c.ID] = c
+	}
+	for _, c := range replies {
+		if parent, ok := nodes[*c.ParentID]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+
+	return comments, total, respcode.SUCCESS
+}
+
+// GetModerationQueue 按状态获取评论，供管理员审核
+func GetModerationQueue(status int, page *pagination.Page) ([]*Comment, int64, int) {
+	if !validCommentStatus(status) {
+		return nil, 0, respcode.ErrorCommentStatusInvalid
+	}
+
+	var comments []*Comment
+	var total int64
+
+	query := db.Model(&Comment{}).Where("status = ?", status)
+	if !page.UseCursor {
+		query.Count(&total)
+	}
+	page.Asc = true
+	if err := query.Preload("User").
+		Scopes(page.Scope("comment.created_at", "comment.id", oldestFirst)).
+		Find(&comments).Error; err != nil {
+		return nil, 0, respcode.ERROR
+	}
+	return pagination.Paginate(page, comments, commentKey), total, respcode.SUCCESS
+}
+
+// ModerateComments 批量设置评论的审核状态，返回实际更新的数量
+func ModerateComments(ids []uint, status int) (int64, int) {
+	if !validCommentStatus(status) {
+		return 0, respcode.ErrorCommentStatusInvalid
+	}
+	if len(ids) == 0 {
+		return 0, respcode.BadRequest
+	}
+
+	result := db.Model(&Comment{}).Where("id IN ?", ids).Update("status", status)
+	if result.Error != nil {
+		utils.Log.Error("审核评论失败:", result.Error)
+		return 0, respcode.ERROR
+	}
+	return result.RowsAffected, respcode.SUCCESS
+}
+
+// oldestFirst 按发表时间从旧到新排序
+func oldestFirst(tx *gorm.DB) *gorm.DB {
+	return tx.Order("comment.created_at, comment.id")
+}
+
+func validCommentStatus(status int) bool {
+	return status >= CommentStatusPending && status <= CommentStatusRejected
+}
+
+// commentCounts 统计文章已通过审核的评论数量
+func commentCounts(articleIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		ArticleID uint
+		Count     int64
+	}
+	if err := db.Model(&Comment{}).
+		Select("article_id, COUNT(*) AS count").
+		Where("article_id IN ? AND status = ?", articleIDs, CommentStatusApproved).
+		Group("article_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"github.com/HauKuen/Annals/internal/utils/render"
+	"gorm.io/gorm"
+)
+
+// excerptLength 自动摘要的最大字符数
+const excerptLength = 200
+
+// renderedColumns 根据正文计算得到、需要随正文一起更新的列
+var renderedColumns = []string{"content_html", "excerpt", "word_count", "reading_time", "toc"}
+
+// renderContent 根据正文和格式计算渲染结果、摘要、字数、阅读时间和目录
+func (a *Article) renderContent() error {
+	contentHTML, err := render.HTML(a.Content, a.Format)
+	if err != nil {
+		return err
+	}
+	text := render.PlainText(contentHTML)
+
+	a.ContentHTML = contentHTML
+	a.WordCount, a.ReadingTime = render.ReadingStats(text)
+	a.TOC = render.TOC(contentHTML)
+
+	// 作者提供了摘要时优先使用
+	if a.Summary != "" {
+		a.Excerpt = render.Excerpt(a.Summary, excerptLength)
+	} else {
+		a.Excerpt = render.Excerpt(text, excerptLength)
+	}
+	return nil
+}
+
+// contentChanged 判断更新是否涉及正文、格式或摘要
+func contentChanged(updates map[string]interface{}) bool {
+	for _, field := range []string{"content", "format", "summary"} {
+		if _, ok := updates[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// backfillRenderedContent 为渲染功能上线前创建的文章生成渲染结果
+func backfillRenderedContent() error {
+	var articles []Article
+	return db.Unscoped().Where("content_html IS NULL OR content_html = '' OR excerpt IS NULL").
+		FindInBatches(&articles, 100, func(_ *gorm.DB, _ int) error {
+			for i := range articles {
+				if err := articles[i].renderContent(); err != nil {
+					return err
+				}
+				if err := db.Unscoped().Model(&articles[i]).Select(renderedColumns).
+					UpdateColumns(&articles[i]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -54,6 +54,14 @@ func InitDb() error {
 		return fmt.Errorf("数据库迁移失败: %w", err)
 	}
 
+	if err := initSearcher(); err != nil {
+		return fmt.Errorf("搜索引擎初始化失败: %w", err)
+	}
+
+	if err := initSpamChecker(); err != nil {
+		return fmt.Errorf("垃圾评论检测器初始化失败: %w", err)
+	}
+
 	utils.Log.Info("数据库初始化成功")
 	return nil
 }
@@ -63,7 +71,19 @@ func autoMigrate() error {
 		return nil
 	}
 
-	if err := db.AutoMigrate(&User{}, &Category{}, &Article{}); err != nil {
+	if err := db.AutoMigrate(&User{}, &Category{}, &Article{}, &ArticleRevision{}, &Tag{}, &SlugRedirect{}, &Comment{}, &ArticleLike{}, &ArticleBookmark{}, &ArticleViewDaily{}, &ArticleReferrer{}, &Series{}); err != nil {
+		return err
+	}
+
+	if err := backfillSlugs(); err != nil {
+		return err
+	}
+
+	if err := backfillRenderedContent(); err != nil {
+		return err
+	}
+
+	if err := backfillPublishedAt(); err != nil {
 		return err
 	}
 
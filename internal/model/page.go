@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// 各列表游标分页使用的键，返回记录的创建时间和ID
+
+// articleKey 文章的游标键
+func articleKey(a Article) (time.Time, uint) {
+	return a.CreatedAt, a.ID
+}
+
+// userKey 用户的游标键，APIUser 的创建时间以字符串形式读出
+func userKey(u APIUser) (time.Time, uint) {
+	t, _ := time.Parse(time.RFC3339Nano, u.CreatedAt)
+	return t, u.ID
+}
+
+// commentKey 评论的游标键
+func commentKey(c *Comment) (time.Time, uint) {
+	return c.CreatedAt, c.ID
+}
+
+// seriesKey 系列的游标键
+func seriesKey(s Series) (time.Time, uint) {
+	return s.CreatedAt, s.ID
+}
+
+// revisionKey 修订版本的游标键
+func revisionKey(r ArticleRevision) (time.Time, uint) {
+	return r.CreatedAt, r.ID
+}
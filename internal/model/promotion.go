@@ -0,0 +1,97 @@
+package model
+
+import (
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activePinned 置顶且未过期的条件
+const activePinned = "(article.pinned AND (article.pinned_until IS NULL OR article.pinned_until > ?))"
+
+// pinnedFirst 将置顶且未过期的文章排在最前面，需与其他排序组合使用
+func pinnedFirst(tx *gorm.DB) *gorm.DB {
+	return tx.Order(clause.Expr{SQL: activePinned + " DESC", Vars: []interface{}{time.Now()}})
+}
+
+// pinnedScope 只查询置顶且未过期的文章
+func pinnedScope(tx *gorm.DB) *gorm.DB {
+	return tx.Where(activePinned, time.Now())
+}
+
+// unpinnedScope 排除置顶且未过期的文章
+func unpinnedScope(tx *gorm.DB) *gorm.DB {
+	return tx.Where("NOT "+activePinned, time.Now())
+}
+
+// featuredScope 只查询推荐且未过期的文章
+func featuredScope(tx *gorm.DB) *gorm.DB {
+	return tx.Where("article.featured AND (article.featured_until IS NULL OR article.featured_until > ?)", time.Now())
+}
+
+// SetArticlePinned 设置文章是否置顶，until 为空时永久置顶
+func SetArticlePinned(id int, pinned bool, until *time.Time) int {
+	return setArticlePromotion(id, "pinned", pinned, until)
+}
+
+// SetArticleFeatured 设置文章是否推荐，until 为空时永久推荐
+func SetArticleFeatured(id int, featured bool, until *time.Time) int {
+	return setArticlePromotion(id, "featured", featured, until)
+}
+
+func setArticlePromotion(id int, column string, enabled bool, until *time.Time) int {
+	var article Article
+	if err := db.First(&article, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return respcode.ErrorArtNotExist
+		}
+		return respcode.ERROR
+	}
+
+	if !enabled {
+		until = nil
+	} else if until != nil && !until.After(time.Now()) {
+		return respcode.ErrorArtPromotionExpiry
+	}
+
+	if err := db.Model(&article).Updates(map[string]interface{}{
+		column:            enabled,
+		column + "_until": until,
+	}).Error; err != nil {
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// GetFeaturedArticles 获取推荐的已发布文章，置顶的在前，其余按发布时间从新到旧
+func GetFeaturedArticles(limit int) ([]Article, int) {
+	var articles []Article
+	if err := db.Scopes(listedScope, featuredScope, listColumns, withRelations, pinnedFirst, newestFirst).
+		Limit(limit).
+		Find(&articles).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+
+	fillArticleExtras(articles)
+	return articles, respcode.SUCCESS
+}
+
+// ExpireArticlePromotions 清除已过期的置顶和推荐标记，返回清除的数量
+//
+// 排序和查询本身已经忽略过期的标记，这里只是让返回的字段与实际状态一致。
+func ExpireArticlePromotions() (int64, error) {
+	now := time.Now()
+	var total int64
+	for _, column := range []string{"pinned", "featured"} {
+		result := db.Model(&Article{}).
+			Where(column+" AND "+column+"_until <= ?", now).
+			Updates(map[string]interface{}{column: false, column + "_until": nil})
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+	}
+	return total, nil
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+)
+
+// articleSortFields 允许排序的字段及其对应的列
+var articleSortFields = map[string]string{
+	"created_at":   "article.created_at",
+	"published_at": "article.published_at",
+	"updated_at":   "article.updated_at",
+	"title":        "article.title",
+	"views":        "article.view_count",
+}
+
+// ArticleQuery 文章列表共用的排序和筛选条件，零值表示不排序或不筛选
+type ArticleQuery struct {
+	Sort  string // 排序字段，见 articleSortFields，为空时使用各列表的默认排序
+	Order string // asc 或 desc，默认 desc
+
+	// 按时间筛选，From 包含、To 不包含，筛选的列见 timeColumn
+	From *time.Time
+	To   *time.Time
+
+	CategoryID uint
+	UserID     uint
+	Status     int
+
+	// 是否允许查看未发布的文章，由调用方根据当前用户的权限设置
+	AllowDrafts bool
+	// 不把置顶的文章排在最前，用于订阅源等严格按时间排列的场景
+	IgnorePinned bool
+}
+
+// Validate 检查排序字段、排序方向、时间范围和状态是否有效
+func (q *ArticleQuery) Validate() int {
+	if q.Sort != "" {
+		if _, ok := articleSortFields[q.Sort]; !ok {
+			return respcode.ErrorArtSortInvalid
+		}
+	}
+	if q.Order != "" && q.Order != "asc" && q.Order != "desc" {
+		return respcode.ErrorArtSortInvalid
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return respcode.ErrorArtFilterInvalid
+	}
+	if q.Status != 0 {
+		if _, ok := articleStatusTransitions[q.Status]; !ok {
+			return respcode.ErrorArtStatusInvalid
+		}
+		if q.Status != ArticleStatusPublished && !q.AllowDrafts {
+			return respcode.ErrorNoPermission
+		}
+	}
+	return respcode.SUCCESS
+}
+
+// IsDefault 是否没有指定任何排序和筛选条件
+func (q *ArticleQuery) IsDefault() bool {
+	return q.Sort == "" && q.From == nil && q.To == nil &&
+		q.CategoryID == 0 && q.UserID == 0 && q.Status == 0
+}
+
+// filter 返回应用筛选条件的 scope，draftsByDefault 为 true 且允许查看未发布文章时，
+// 未指定状态则返回所有状态和可见性的文章，否则只返回已发布的公开文章
+func (q *ArticleQuery) filter(draftsByDefault bool) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		owner := draftsByDefault && q.AllowDrafts
+		switch {
+		case q.Status != 0:
+			tx = tx.Where("article.status = ?", q.Status)
+		case !owner:
+			tx = tx.Scopes(publishedScope)
+		}
+		if !owner {
+			tx = tx.Where("article.visibility = ?", ArticleVisibilityPublic)
+		}
+		if q.From != nil {
+			tx = tx.Where(q.timeColumn()+" >= ?", *q.From)
+		}
+		if q.To != nil {
+			tx = tx.Where(q.timeColumn()+" < ?", *q.To)
+		}
+		if q.CategoryID != 0 {
+			tx = tx.Where("article.category_id = ?", q.CategoryID)
+		}
+		if q.UserID != 0 {
+			tx = tx.Where("article.user_id = ?", q.UserID)
+		}
+		return tx
+	}
+}
+
+// timeColumn 时间筛选使用的列，与列表排序使用的时间一致：
+// 按创建时间或更新时间排序时使用该列，否则与归档一样按发布时间，未发布的文章按创建时间
+func (q *ArticleQuery) timeColumn() string {
+	switch q.Sort {
+	case "created_at", "updated_at":
+		return articleSortFields[q.Sort]
+	}
+	return archiveDate
+}
+
+// sort 返回应用排序的 scope，未指定排序字段时使用 fallback；
+// pinned 为 true 时无论按哪个字段排序，置顶的文章总是排在最前
+func (q *ArticleQuery) sort(pinned bool, fallback ...func(*gorm.DB) *gorm.DB) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if pinned {
+			tx = tx.Scopes(pinnedFirst)
+		}
+		column, ok := articleSortFields[q.Sort]
+		if !ok {
+			return tx.Scopes(fallback...)
+		}
+
+		direction := " DESC"
+		if q.Order == "asc" {
+			direction = " ASC"
+		}
+		// 以ID作为第二排序条件，保证分页稳定
+		return tx.Order(column + direction).Order("article.id" + direction)
+	}
+}
+
+// validatePage 在 Validate 的基础上检查分页方式，游标分页只能按创建时间排序
+func (q *ArticleQuery) validatePage(page *pagination.Page) int {
+	if code := q.Validate(); code != respcode.SUCCESS {
+		return code
+	}
+	if page.UseCursor {
+		if q.Sort != "" && q.Sort != "created_at" {
+			return respcode.ErrorCursorSort
+		}
+		// 游标按创建时间排列，时间筛选也要使用创建时间
+		q.Sort = "created_at"
+	}
+	return respcode.SUCCESS
+}
+
+// paginate 返回排序和分页的 scope，排序规则同 sort；游标分页时按创建时间排序，忽略 fallback
+func (q *ArticleQuery) paginate(page *pagination.Page, pinned bool, fallback ...func(*gorm.DB) *gorm.DB) func(*gorm.DB) *gorm.DB {
+	page.Asc = q.Order == "asc"
+	return page.Scope("article.created_at", "article.id", q.sort(pinned, fallback...))
+}
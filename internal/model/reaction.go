@@ -0,0 +1,143 @@
+package model
+
+import (
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ArticleLike 用户点赞文章，联合主键保证同一用户对同一文章只计一次
+type ArticleLike struct {
+	ArticleID uint `gorm:"primaryKey;autoIncrement:false"`
+	UserID    uint `gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt time.Time
+}
+
+// ArticleBookmark 用户收藏文章
+type ArticleBookmark struct {
+	ArticleID uint `gorm:"primaryKey;autoIncrement:false"`
+	UserID    uint `gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt time.Time
+}
+
+// LikeArticle 点赞文章，重复点赞不报错
+func LikeArticle(articleID int, userID uint) int {
+	return addReaction(articleID, &ArticleLike{ArticleID: uint(articleID), UserID: userID})
+}
+
+// UnlikeArticle 取消点赞，未点赞时不报错
+func UnlikeArticle(articleID int, userID uint) int {
+	return removeReaction(&ArticleLike{}, articleID, userID)
+}
+
+// BookmarkArticle 收藏文章，重复收藏不报错
+func BookmarkArticle(articleID int, userID uint) int {
+	return addReaction(articleID, &ArticleBookmark{ArticleID: uint(articleID), UserID: userID})
+}
+
+// UnbookmarkArticle 取消收藏，未收藏时不报错
+func UnbookmarkArticle(articleID int, userID uint) int {
+	return removeReaction(&ArticleBookmark{}, articleID, userID)
+}
+
+// addReaction 插入点赞或收藏记录，只能对已发布的文章操作
+//
+// 并发的重复请求由联合主键去重，冲突时忽略插入，因此只有一条生效且都返回成功。
+func addReaction(articleID int, reaction interface{}) int {
+	var article Article
+	if err := db.Scopes(accessibleScope).Select("id").First(&article, articleID).Error; err != nil {
+		return respcode.ErrorArtNotExist
+	}
+
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction).Error; err != nil {
+		utils.Log.Error("保存文章互动失败:", err)
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+func removeReaction(model interface{}, articleID int, userID uint) int {
+	if err := db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(model).Error; err != nil {
+		utils.Log.Error("删除文章互动失败:", err)
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// GetUserBookmarks 获取用户收藏的已发布文章，按收藏时间倒序
+//
+// 先按收藏记录分页，再加载对应的文章，游标分页的键为收藏时间和文章ID。
+func GetUserBookmarks(userID uint, page *pagination.Page) ([]Article, int64, int) {
+	var bookmarks []ArticleBookmark
+	var total int64
+
+	query := db.Model(&ArticleBookmark{}).
+		Joins("JOIN article ON article.id = article_bookmark.article_id AND article.deleted_at IS NULL").
+		Scopes(accessibleScope).
+		Where("article_bookmark.user_id = ?", userID)
+
+	if !page.UseCursor {
+		query.Count(&total)
+	}
+	newest := func(tx *gorm.DB) *gorm.DB { return tx.Order("article_bookmark.created_at DESC") }
+	if err := query.Select("article_bookmark.*").
+		Scopes(page.Scope("article_bookmark.created_at", "article_bookmark.article_id", newest)).
+		Find(&bookmarks).Error; err != nil {
+		return nil, 0, respcode.ERROR
+	}
+	bookmarks = pagination.Paginate(page, bookmarks, func(b ArticleBookmark) (time.Time, uint) {
+		return b.CreatedAt, b.ArticleID
+	})
+	if len(bookmarks) == 0 {
+		return []Article{}, total, respcode.SUCCESS
+	}
+
+	ids := make([]uint, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.ArticleID
+	}
+	var found []Article
+	if err := db.Scopes(listColumns, withRelations).Where("article.id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, 0, respcode.ERROR
+	}
+
+	// 按收藏顺序排列
+	byID := make(map[uint]Article, len(found))
+	for _, article := range found {
+		byID[article.ID] = article
+	}
+	articles := make([]Article, 0, len(found))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			articles = append(articles, article)
+		}
+	}
+
+	fillArticleExtras(articles)
+	return articles, total, respcode.SUCCESS
+}
+
+// reactionCounts 统计文章的点赞或收藏数量
+func reactionCounts(model interface{}, articleIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		ArticleID uint
+		Count     int64
+	}
+	if err := db.Model(model).
+		Select("article_id, COUNT(*) AS count").
+		Where("article_id IN ?", articleIDs).
+		Group("article_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Count
+	}
+	return counts, nil
+}
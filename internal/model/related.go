@@ -0,0 +1,216 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+)
+
+// 相关文章的评分权重
+const (
+	relatedTagWeight      = 3.0 // 每个共同标签
+	relatedCategoryWeight = 2.0 // 同一分类
+	relatedKeywordWeight  = 1.0 // 标题中每个共同关键词
+	relatedRecencyWeight  = 1.0 // 新发布的文章加分，随时间衰减
+	relatedRecencyDays    = 30.0
+)
+
+// 缓存的相关文章数量，以及参与评分的候选文章数量
+const (
+	relatedMax        = 20
+	relatedCandidates = 200
+	relatedRecent     = 50
+)
+
+// relatedCache 缓存每篇文章的相关文章ID，任何文章变化时整体清空
+//
+// gen 在每次清空时递增，计算开始后缓存被清空的结果已经过期，不会写入缓存。
+var relatedCache = struct {
+	sync.RWMutex
+	ids map[uint][]uint
+	gen uint64
+}{ids: make(map[uint][]uint)}
+
+// invalidateRelated 清空相关文章缓存
+func invalidateRelated() {
+	relatedCache.Lock()
+	relatedCache.ids = make(map[uint][]uint)
+	relatedCache.gen++
+	relatedCache.Unlock()
+}
+
+// GetRelatedArticles 获取与文章相关的已发布文章，按相关度从高到低排列
+//
+// 相关度由共同标签、同一分类、标题关键词和发布时间综合计算，不包含文章本身。
+func GetRelatedArticles(id int, limit int) ([]Article, int) {
+	// 在读取数据库之前记录缓存的代数
+	relatedCache.RLock()
+	gen := relatedCache.gen
+	relatedCache.RUnlock()
+
+	var article Article
+	if err := db.Preload("Tags").Omit("content", "content_html", "toc").First(&article, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, respcode.ErrorArtNotExist
+		}
+		return nil, respcode.ERROR
+	}
+
+	relatedCache.RLock()
+	ids, ok := relatedCache.ids[article.ID]
+	relatedCache.RUnlock()
+
+	if !ok {
+		var err error
+		ids, err = relatedArticleIDs(&article)
+		if err != nil {
+			utils.Log.Error("计算相关文章失败:", err)
+			return nil, respcode.ERROR
+		}
+		relatedCache.Lock()
+		if relatedCache.gen == gen {
+			relatedCache.ids[article.ID] = ids
+		}
+		relatedCache.Unlock()
+	}
+
+	if limit < len(ids) {
+		ids = ids[:limit]
+	}
+	if len(ids) == 0 {
+		return []Article{}, respcode.SUCCESS
+	}
+
+	var found []Article
+	if err := db.Scopes(listedScope, listColumns, withRelations).Where("article.id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+
+	byID := make(map[uint]Article, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+	articles := make([]Article, 0, len(found))
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			articles = append(articles, a)
+		}
+	}
+
+	fillArticleExtras(articles)
+	return articles, respcode.SUCCESS
+}
+
+// relatedArticleIDs 从同分类、同标签和最新的文章中选出候选并评分，返回得分最高的文章ID
+func relatedArticleIDs(article *Article) ([]uint, error) {
+	tagIDs := make([]uint, len(article.Tags))
+	for i, tag := range article.Tags {
+		tagIDs[i] = tag.ID
+	}
+
+	candidateColumns := func(tx *gorm.DB) *gorm.DB {
+		return tx.Select("article.id", "article.title", "article.category_id", "article.published_at", "article.created_at").
+			Preload("Tags").
+			Where("article.id <> ?", article.ID)
+	}
+
+	var similar []Article
+	query := db.Scopes(listedScope, candidateColumns)
+	if len(tagIDs) > 0 {
+		query = query.Where("article.category_id = ? OR article.id IN (?)", article.CategoryID,
+			db.Table("article_tag").Select("article_id").Where("tag_id IN ?", tagIDs))
+	} else {
+		query = query.Where("article.category_id = ?", article.CategoryID)
+	}
+	if err := query.Scopes(newestFirst).Limit(relatedCandidates).Find(&similar).Error; err != nil {
+		return nil, err
+	}
+
+	// 同分类、同标签的文章不足时，最新的文章也可以作为推荐
+	var recent []Article
+	if err := db.Scopes(listedScope, candidateColumns, newestFirst).Limit(relatedRecent).Find(&recent).Error; err != nil {
+		return nil, err
+	}
+
+	keywords := titleKeywords(article.Title)
+	tagSet := make(map[uint]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		tagSet[id] = true
+	}
+
+	type scored struct {
+		id    uint
+		score float64
+	}
+	seen := make(map[uint]bool)
+	var results []scored
+	now := time.Now()
+	for _, c := range append(similar, recent...) {
+		if seen[c.ID] {
+			continue
+		}
+		seen[c.ID] = true
+
+		score := 0.0
+		for _, tag := range c.Tags {
+			if tagSet[tag.ID] {
+				score += relatedTagWeight
+			}
+		}
+		if c.CategoryID == article.CategoryID {
+			score += relatedCategoryWeight
+		}
+		for word := range titleKeywords(c.Title) {
+			if keywords[word] {
+				score += relatedKeywordWeight
+			}
+		}
+
+		published := c.CreatedAt
+		if c.PublishedAt != nil {
+			published = *c.PublishedAt
+		}
+		ageDays := now.Sub(published).Hours() / 24
+		if ageDays < 0 {
+			ageDays = 0
+		}
+		score += relatedRecencyWeight / (1 + ageDays/relatedRecencyDays)
+
+		results = append(results, scored{c.ID, score})
+	}
+
+	// 按得分从高到低排序，得分相同时较新的文章在前
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].id > results[j].id
+	})
+
+	ids := make([]uint, 0, relatedMax)
+	for i := 0; i < len(results) && i < relatedMax; i++ {
+		ids = append(ids, results[i].id)
+	}
+	return ids, nil
+}
+
+// titleKeywords 提取标题中的关键词，忽略过短的词
+func titleKeywords(title string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	keywords := make(map[string]bool, len(words))
+	for _, word := range words {
+		if len([]rune(word)) >= 2 {
+			keywords[word] = true
+		}
+	}
+	return keywords
+}
@@ -0,0 +1,215 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ArticleRevision 文章修订版本，创建后不可修改
+type ArticleRevision struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	ArticleID  uint      `gorm:"not null;uniqueIndex:idx_article_version" json:"article_id"`
+	Version    int       `gorm:"not null;uniqueIndex:idx_article_version" json:"version"`
+	UserID     uint      `gorm:"not null" json:"user_id"`
+	Title      string    `gorm:"type:varchar(100);not null" json:"title"`
+	Content    string    `gorm:"type:longtext;not null" json:"content,omitempty"`
+	Format     string    `gorm:"type:varchar(20);not null;default:'markdown'" json:"format"`
+	CategoryID uint      `gorm:"not null" json:"category_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// saveRevision 保存文章当前内容为新的修订版本
+func saveRevision(tx *gorm.DB, article *Article, editorID uint) error {
+	var version int
+	if err := tx.Model(&ArticleRevision{}).
+		Where("article_id = ?", article.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&ArticleRevision{
+		ArticleID:  article.ID,
+		Version:    version + 1,
+		UserID:     editorID,
+		Title:      article.Title,
+		Content:    article.Content,
+		Format:     article.Format,
+		CategoryID: article.CategoryID,
+	}).Error
+}
+
+// ensureBaseRevision 为修订功能上线前创建的文章补存当前版本
+func ensureBaseRevision(tx *gorm.DB, article *Article) error {
+	var count int64
+	if err := tx.Model(&ArticleRevision{}).Where("article_id = ?", article.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	revision := baseRevision(article)
+	return tx.Create(&revision).Error
+}
+
+// baseRevision 由文章当前内容生成第一个修订版本，作者和时间沿用文章的作者和最后修改时间
+func baseRevision(article *Article) ArticleRevision {
+	return ArticleRevision{
+		ArticleID:  article.ID,
+		Version:    1,
+		UserID:     article.UserID,
+		Title:      article.Title,
+		Content:    article.Content,
+		Format:     article.Format,
+		CategoryID: article.CategoryID,
+		CreatedAt:  article.UpdatedAt,
+	}
+}
+
+// applyArticleUpdate 更新文章并记录修订版本，tagNames 为 nil 时不修改标签
+func applyArticleUpdate(id int, editorID uint, updates map[string]interface{}, tagNames []string) int {
+	code := respcode.SUCCESS
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var article Article
+		// 锁定文章行，保证并发编辑时版本号连续
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&article, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				code = respcode.ErrorArtNotExist
+			}
+			return err
+		}
+
+		if categoryID, ok := updates["category_id"].(uint); ok {
+			if err := lockCategory(tx, categoryID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					code = respcode.ErrorCateNotExist
+				}
+				return err
+			}
+		}
+
+		if err := ensureBaseRevision(tx, &article); err != nil {
+			return err
+		}
+		if tagNames != nil {
+			if err := setArticleTags(tx, &article, tagNames); err != nil {
+				return err
+			}
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		if newSlug, ok := updates["slug"].(string); ok {
+			if err := recordSlugChange(tx, SlugKindArticle, article.ID, article.Slug, newSlug); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&article).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&article, id).Error; err != nil {
+			return err
+		}
+		if contentChanged(updates) {
+			if err := article.renderContent(); err != nil {
+				return err
+			}
+			if err := tx.Model(&article).Select(renderedColumns).Updates(&article).Error; err != nil {
+				return err
+			}
+		}
+		if !revisionFieldsChanged(updates) {
+			return nil
+		}
+		return saveRevision(tx, &article, editorID)
+	})
+
+	if err != nil {
+		if code == respcode.SUCCESS {
+			utils.Log.Error("更新文章失败:", err)
+			code = respcode.ERROR
+		}
+		return code
+	}
+
+	articleChanged(uint(id))
+	return code
+}
+
+// revisionFieldsChanged 判断更新是否涉及修订版本记录的字段
+func revisionFieldsChanged(updates map[string]interface{}) bool {
+	for _, field := range []string{"title", "content", "format", "category_id"} {
+		if _, ok := updates[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetArticleRevisions 获取文章的修订历史，不包含正文
+func GetArticleRevisions(articleID int, page *pagination.Page) ([]ArticleRevision, int64, int) {
+	var revisions []ArticleRevision
+	var total int64
+
+	query := db.Model(&ArticleRevision{}).Where("article_id = ?", articleID)
+	if !page.UseCursor {
+		query.Count(&total)
+	}
+	byVersion := func(tx *gorm.DB) *gorm.DB { return tx.Order("version DESC") }
+	if err := query.Omit("content").
+		Scopes(page.Scope("article_revision.created_at", "article_revision.id", byVersion)).
+		Find(&revisions).Error; err != nil {
+		return nil, 0, respcode.ERROR
+	}
+
+	revisions = pagination.Paginate(page, revisions, revisionKey)
+	return revisions, total, respcode.SUCCESS
+}
+
+// GetArticleRevision 获取文章的某个修订版本
+func GetArticleRevision(articleID int, version int) (ArticleRevision, int) {
+	var revision ArticleRevision
+	err := db.Where("article_id = ? AND version = ?", articleID, version).First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return revision, respcode.ErrorRevisionNotExist
+		}
+		return revision, respcode.ERROR
+	}
+	return revision, respcode.SUCCESS
+}
+
+// RestoreArticleRevision 将文章恢复到指定修订版本，恢复操作本身会产生新的修订版本
+func RestoreArticleRevision(articleID int, version int, editorID uint) int {
+	revision, code := GetArticleRevision(articleID, version)
+	if code != respcode.SUCCESS {
+		return code
+	}
+
+	// 检查分类是否仍然存在
+	var category Category
+	if err := db.First(&category, revision.CategoryID).Error; err != nil {
+		return respcode.ErrorCateNotExist
+	}
+
+	return applyArticleUpdate(articleID, editorID, revisionUpdates(&revision), nil)
+}
+
+// revisionUpdates 恢复到修订版本时需要更新的文章字段
+func revisionUpdates(revision *ArticleRevision) map[string]interface{} {
+	return map[string]interface{}{
+		"title":       revision.Title,
+		"content":     revision.Content,
+		"format":      revision.Format,
+		"category_id": revision.CategoryID,
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/HauKuen/Annals/internal/utils/render"
+)
+
+// TestRestoreBaseRevisionFormat 恢复补存的第一个版本时保持文章原有的内容格式
+func TestRestoreBaseRevisionFormat(t *testing.T) {
+	for _, format := range []string{render.FormatMarkdown, render.FormatHTML, render.FormatPlain} {
+		t.Run(format, func(t *testing.T) {
+			article := &Article{
+				Title:      "title",
+				Content:    "line one\nline two",
+				Format:     format,
+				CategoryID: 3,
+				UserID:     2,
+			}
+			article.ID = 1
+
+			revision := baseRevision(article)
+			if revision.Version != 1 || revision.UserID != article.UserID {
+				t.Fatalf("base revision = %+v", revision)
+			}
+
+			updates := revisionUpdates(&revision)
+			want := map[string]interface{}{
+				"title":       article.Title,
+				"content":     article.Content,
+				"format":      format,
+				"category_id": article.CategoryID,
+			}
+			for key, value := range want {
+				if updates[key] != value {
+					t.Errorf("updates[%q] = %v, want %v", key, updates[key], value)
+				}
+			}
+		})
+	}
+}
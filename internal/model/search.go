@@ -0,0 +1,237 @@
+package model
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/render"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Searcher 文章全文搜索引擎
+type Searcher interface {
+	// Index 更新文章的索引，文章不存在或未发布时从索引中移除
+	Index(articleID uint) error
+	// Remove 从索引中移除文章
+	Remove(articleID uint) error
+	// Search 按相关度从高到低返回匹配的文章及匹配总数
+	Search(keyword string, limit int, offset int) ([]SearchHit, int64, error)
+	// Rebuild 清空并重建全部索引，返回索引的文章数量
+	Rebuild() (int64, error)
+}
+
+// SearchHit 一条搜索结果
+type SearchHit struct {
+	ArticleID uint
+	Score     float64
+}
+
+// Highlight 搜索结果的相关度和高亮片段
+type Highlight struct {
+	Score   float64 `json:"score"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+}
+
+// searchEngines 可用的搜索引擎，键为配置中的 search.engine
+var searchEngines = map[string]func(*gorm.DB) (Searcher, error){
+	"mysql": newMySQLSearcher,
+}
+
+var searcher Searcher
+
+// RegisterSearchEngine 注册自定义搜索引擎，需在 InitDb 之前调用
+func RegisterSearchEngine(name string, factory func(*gorm.DB) (Searcher, error)) {
+	searchEngines[name] = factory
+}
+
+// initSearcher 根据配置初始化搜索引擎
+func initSearcher() error {
+	factory, ok := searchEngines[utils.SearchEngine]
+	if !ok {
+		return fmt.Errorf("未知的搜索引擎: %s", utils.SearchEngine)
+	}
+
+	s, err := factory(db)
+	if err != nil {
+		return err
+	}
+	searcher = s
+	return nil
+}
+
+// articleChanged 在文章写入成功后调用，同步更新搜索索引并清空相关文章和 sitemap 的缓存
+func articleChanged(ids ...uint) {
+	for _, id := range ids {
+		if err := searcher.Index(id); err != nil {
+			utils.Log.Error("更新搜索索引失败:", err)
+		}
+	}
+	if len(ids) > 0 {
+		invalidateRelated()
+		invalidateSitemap()
+	}
+}
+
+// RebuildSearchIndex 重建搜索索引
+func RebuildSearchIndex() (int64, int) {
+	count, err := searcher.Rebuild()
+	if err != nil {
+		utils.Log.Error("重建搜索索引失败:", err)
+		return 0, respcode.ERROR
+	}
+	return count, respcode.SUCCESS
+}
+
+// searchFilterLimit 指定了排序或筛选条件时，从搜索引擎中取出参与筛选的最大结果数
+const searchFilterLimit = 1000
+
+// SearchArticles 全文搜索已发布的文章，结果默认按相关度排序并附带高亮片段
+//
+// 指定了排序或筛选条件时，在相关度最高的 searchFilterLimit 条结果中筛选和排序。
+// 游标分页时同样只在这些结果中按创建时间分页，不按相关度排序，也不统计总数。
+func SearchArticles(keyword string, q *ArticleQuery, page *pagination.Page) ([]Article, int64, int) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, 0, respcode.BadRequest
+	}
+	if code := q.validatePage(page); code != respcode.SUCCESS {
+		return nil, 0, code
+	}
+
+	limit, offset := page.Size, (page.Num-1)*page.Size
+	if !q.IsDefault() {
+		limit, offset = searchFilterLimit, 0
+	}
+
+	hits, total, err := searcher.Search(keyword, limit, offset)
+	if err != nil {
+		utils.Log.Error("搜索文章失败:", err)
+		return nil, 0, respcode.ERROR
+	}
+	if len(hits) == 0 {
+		return []Article{}, total, respcode.SUCCESS
+	}
+
+	ids := make([]uint, len(hits))
+	scores := make(map[uint]float64, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ArticleID
+		scores[hit.ArticleID] = hit.Score
+	}
+
+	var articles []Article
+	if q.IsDefault() {
+		var found []Article
+		if err := db.Scopes(listedScope, withRelations).Where("article.id IN ?", ids).Find(&found).Error; err != nil {
+			return nil, 0, respcode.ERROR
+		}
+
+		// 按相关度顺序排列，索引中已失效的文章会被跳过
+		byID := make(map[uint]Article, len(found))
+		for _, article := range found {
+			byID[article.ID] = article
+		}
+		articles = make([]Article, 0, len(found))
+		for _, id := range ids {
+			if article, ok := byID[id]; ok {
+				articles = append(articles, article)
+			}
+		}
+	} else {
+		// 未指定排序字段时保持相关度顺序
+		byRelevance := func(tx *gorm.DB) *gorm.DB {
+			return tx.Order(clause.Expr{SQL: "FIELD(article.id, ?)", Vars: []interface{}{ids}})
+		}
+		query := db.Model(&Article{}).Scopes(q.filter(false)).Where("article.id IN ?", ids)
+		if !page.UseCursor {
+			query.Count(&total)
+		}
+		if err := query.Scopes(withRelations, q.paginate(page, false, byRelevance)).Find(&articles).Error; err != nil {
+			return nil, 0, respcode.ERROR
+		}
+		articles = pagination.Paginate(page, articles, articleKey)
+	}
+
+	terms := strings.Fields(keyword)
+	for i := range articles {
+		article := &articles[i]
+		article.Highlight = &Highlight{
+			Score:   scores[article.ID],
+			Title:   highlight(article.Title, terms),
+			Snippet: snippet(render.PlainText(article.ContentHTML), terms, 80),
+		}
+		// 与其他列表接口一致，不返回正文
+		article.Content, article.ContentHTML, article.TOC = "", "", nil
+	}
+
+	fillArticleExtras(articles)
+	return articles, total, respcode.SUCCESS
+}
+
+// highlight 转义文本并用 <em> 标记出所有关键词
+func highlight(text string, terms []string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		// 取最长的匹配，忽略大小写
+		matched := 0
+		for _, term := range terms {
+			n := len(term)
+			if n > matched && i+n <= len(text) && strings.EqualFold(text[i:i+n], term) {
+				matched = n
+			}
+		}
+		if matched > 0 {
+			b.WriteString("<em>" + html.EscapeString(text[i:i+matched]) + "</em>")
+			i += matched
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		b.WriteString(html.EscapeString(text[i : i+size]))
+		i += size
+	}
+	return b.String()
+}
+
+// snippet 截取第一个关键词附近的文本作为摘要，radius 为关键词前后保留的字符数
+func snippet(text string, terms []string, radius int) string {
+	runes := []rune(text)
+	lower := strings.ToLower(text)
+
+	pos := -1
+	for _, term := range terms {
+		if idx := strings.Index(lower, strings.ToLower(term)); idx >= 0 {
+			p := utf8.RuneCountInString(lower[:idx])
+			if pos < 0 || p < pos {
+				pos = p
+			}
+		}
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	start := pos - radius
+	if start < 0 {
+		start = 0
+	}
+	end := pos + radius
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	result := highlight(strings.Join(strings.Fields(string(runes[start:end])), " "), terms)
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils/render"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fullTextIndex 搜索表上的全文索引名
+const fullTextIndex = "ft_article_search"
+
+// ArticleSearch MySQL 搜索引擎使用的索引表，只包含已发布的文章
+type ArticleSearch struct {
+	ArticleID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Title     string `gorm:"type:varchar(100);not null"`
+	Content   string `gorm:"type:longtext;not null"`
+	Tags      string `gorm:"type:text;not null"`
+	UpdatedAt time.Time
+}
+
+// mysqlSearcher 基于 MySQL FULLTEXT 索引和 ngram 分词器的搜索引擎，支持中文
+type mysqlSearcher struct {
+	db *gorm.DB
+}
+
+func newMySQLSearcher(db *gorm.DB) (Searcher, error) {
+	if err := db.AutoMigrate(&ArticleSearch{}); err != nil {
+		return nil, err
+	}
+
+	if !db.Migrator().HasIndex(&ArticleSearch{}, fullTextIndex) {
+		if err := db.Exec("CREATE FULLTEXT INDEX " + fullTextIndex +
+			" ON article_search (title, content, tags) WITH PARSER ngram").Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &mysqlSearcher{db: db}, nil
+}
+
+func (s *mysqlSearcher) Index(articleID uint) error {
+	var article Article
+	err := s.db.Preload("Tags").First(&article, articleID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.Remove(articleID)
+	}
+	if err != nil {
+		return err
+	}
+	if !article.IsListed() {
+		return s.Remove(articleID)
+	}
+
+	return s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(searchDocument(&article)).Error
+}
+
+func (s *mysqlSearcher) Remove(articleID uint) error {
+	return s.db.Where("article_id = ?", articleID).Delete(&ArticleSearch{}).Error
+}
+
+func (s *mysqlSearcher) Search(keyword string, limit int, offset int) ([]SearchHit, int64, error) {
+	const match = "MATCH(title, content, tags) AGAINST (? IN NATURAL LANGUAGE MODE)"
+
+	var total int64
+	if err := s.db.Model(&ArticleSearch{}).Where(match, keyword).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []SearchHit
+	err := s.db.Model(&ArticleSearch{}).
+		Select("article_id, "+match+" AS score", keyword).
+		Where(match, keyword).
+		Order("score DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&hits).Error
+	return hits, total, err
+}
+
+func (s *mysqlSearcher) Rebuild() (int64, error) {
+	var count int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&ArticleSearch{}).Error; err != nil {
+			return err
+		}
+
+		var articles []Article
+		return tx.Scopes(listedScope).Preload("Tags").
+			FindInBatches(&articles, 200, func(batch *gorm.DB, _ int) error {
+				docs := make([]*ArticleSearch, len(articles))
+				for i := range articles {
+					docs[i] = searchDocument(&articles[i])
+				}
+				count += int64(len(docs))
+				return tx.Create(docs).Error
+			}).Error
+	})
+	return count, err
+}
+
+// searchDocument 将文章转换为索引记录
+func searchDocument(article *Article) *ArticleSearch {
+	tags := make([]string, len(article.Tags))
+	for i, tag := range article.Tags {
+		tags[i] = tag.Name
+	}
+
+	return &ArticleSearch{
+		ArticleID: article.ID,
+		Title:     article.Title,
+		Content:   render.PlainText(article.ContentHTML),
+		Tags:      strings.Join(tags, " "),
+	}
+}
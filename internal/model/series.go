@@ -0,0 +1,241 @@
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Series 系列，将多篇文章按顺序组织成连载
+type Series struct {
+	gorm.Model
+	Title       string `gorm:"type:varchar(100);not null" json:"title"`
+	Description string `gorm:"type:varchar(500)" json:"description"`
+	UserID      uint   `gorm:"not null;index" json:"user_id"`
+
+	User Author `gorm:"foreignKey:UserID" json:"user"`
+
+	// 系列中的文章，按顺序排列
+	Articles []Article `gorm:"-" json:"articles,omitempty"`
+}
+
+// SeriesNav 文章详情中的系列导航
+type SeriesNav struct {
+	ID    uint        `json:"id"`
+	Title string      `json:"title"`
+	Part  int         `json:"part"`
+	Total int         `json:"total"`
+	Prev  *SeriesLink `json:"prev"`
+	Next  *SeriesLink `json:"next"`
+}
+
+// SeriesLink 系列中相邻文章的链接
+type SeriesLink struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// seriesOrder 系列内文章的排序
+func seriesOrder(tx *gorm.DB) *gorm.DB {
+	return tx.Order("article.series_order, article.id")
+}
+
+// CreateSeries 创建系列
+func CreateSeries(series *Series) int {
+	series.Title = strings.TrimSpace(series.Title)
+	if series.Title == "" {
+		return respcode.ErrorSeriesTitleEmpty
+	}
+
+	if err := db.Create(series).Error; err != nil {
+		utils.Log.Error("创建系列失败:", err)
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// GetSeries 获取系列及其中的文章，includeDrafts 为 true 时包含未发布的文章
+func GetSeries(id int, includeDrafts bool) (Series, int) {
+	var series Series
+	if err := db.Preload("User").First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return series, respcode.ErrorSeriesNotExist
+		}
+		return series, respcode.ERROR
+	}
+
+	query := db.Scopes(listColumns, withRelations, seriesOrder).Where("series_id = ?", series.ID)
+	if !includeDrafts {
+		query = query.Scopes(listedScope)
+	}
+	if err := query.Find(&series.Articles).Error; err != nil {
+		return series, respcode.ERROR
+	}
+
+	fillArticleExtras(series.Articles)
+	return series, respcode.SUCCESS
+}
+
+// GetSeriesList 获取系列列表，最新创建的在前
+func GetSeriesList(page *pagination.Page) ([]Series, int64) {
+	var list []Series
+	var total int64
+
+	if !page.UseCursor {
+		db.Model(&Series{}).Count(&total)
+	}
+	byID := func(tx *gorm.DB) *gorm.DB { return tx.Order("id DESC") }
+	db.Preload("User").Scopes(page.Scope("series.created_at", "series.id", byID)).Find(&list)
+	return pagination.Paginate(page, list, seriesKey), total
+}
+
+// EditSeries 修改系列的标题和简介，只更新非空字段
+func EditSeries(id int, data *Series) int {
+	var series Series
+	if err := db.First(&series, id).Error; err != nil {
+		return respcode.ErrorSeriesNotExist
+	}
+
+	updates := make(map[string]interface{})
+	if title := strings.TrimSpace(data.Title); title != "" {
+		updates["title"] = title
+	}
+	if data.Description != "" {
+		updates["description"] = data.Description
+	}
+	if len(updates) == 0 {
+		return respcode.SUCCESS
+	}
+
+	if err := db.Model(&series).Updates(updates).Error; err != nil {
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// DeleteSeries 删除系列，系列中的文章保留并移出系列
+func DeleteSeries(id int) int {
+	var series Series
+	if err := db.First(&series, id).Error; err != nil {
+		return respcode.ErrorSeriesNotExist
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Article{}).Where("series_id = ?", series.ID).
+			Updates(map[string]interface{}{"series_id": nil, "series_order": 0}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&series).Error
+	})
+	if err != nil {
+		utils.Log.Error("删除系列失败:", err)
+		return respcode.ERROR
+	}
+	return respcode.SUCCESS
+}
+
+// SetSeriesArticles 按给定顺序设置系列中的文章
+//
+// 只能加入系列作者本人的文章；不在列表中的原有文章会被移出系列，
+// 已属于其他系列的文章会被移入本系列。
+func SetSeriesArticles(id int, articleIDs []uint) int {
+	var series Series
+	if err := db.First(&series, id).Error; err != nil {
+		return respcode.ErrorSeriesNotExist
+	}
+
+	seen := make(map[uint]bool, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if seen[articleID] {
+			return respcode.ErrorSeriesArticleInvalid
+		}
+		seen[articleID] = true
+	}
+
+	code := respcode.SUCCESS
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 锁定要加入的文章，检查作者后到更新完成前文章不会被转移或删除
+		if len(articleIDs) > 0 {
+			var owned []uint
+			if err := tx.Model(&Article{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id IN ? AND user_id = ?", articleIDs, series.UserID).
+				Pluck("id", &owned).Error; err != nil {
+				return err
+			}
+			if len(owned) != len(articleIDs) {
+				code = respcode.ErrorSeriesArticleInvalid
+				return errors.New("series article invalid")
+			}
+		}
+
+		query := tx.Model(&Article{}).Where("series_id = ?", series.ID)
+		if len(articleIDs) > 0 {
+			query = query.Where("id NOT IN ?", articleIDs)
+		}
+		if err := query.Updates(map[string]interface{}{"series_id": nil, "series_order": 0}).Error; err != nil {
+			return err
+		}
+
+		for i, articleID := range articleIDs {
+			if err := tx.Model(&Article{}).Where("id = ?", articleID).
+				Updates(map[string]interface{}{"series_id": series.ID, "series_order": i + 1}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if code == respcode.SUCCESS {
+			utils.Log.Error("设置系列文章失败:", err)
+			code = respcode.ERROR
+		}
+		return code
+	}
+	return respcode.SUCCESS
+}
+
+// seriesNav 计算文章在系列中的位置和相邻的已发布文章，文章不属于任何系列时返回 nil
+func seriesNav(article *Article) (*SeriesNav, error) {
+	if article.SeriesID == nil {
+		return nil, nil
+	}
+
+	var series Series
+	if err := db.First(&series, *article.SeriesID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// 作者预览未发布的文章或通过链接访问不公开列出的文章时，该文章也参与排序
+	var parts []SeriesLink
+	if err := db.Model(&Article{}).Scopes(seriesOrder).
+		Select("id, title, slug").
+		Where("series_id = ? AND ((status = ? AND visibility = ?) OR id = ?)",
+			series.ID, ArticleStatusPublished, ArticleVisibilityPublic, article.ID).
+		Scan(&parts).Error; err != nil {
+		return nil, err
+	}
+
+	nav := &SeriesNav{ID: series.ID, Title: series.Title, Total: len(parts)}
+	for i := range parts {
+		if parts[i].ID != article.ID {
+			continue
+		}
+		nav.Part = i + 1
+		if i > 0 {
+			nav.Prev = &parts[i-1]
+		}
+		if i < len(parts)-1 {
+			nav.Next = &parts[i+1]
+		}
+	}
+	return nav, nil
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+)
+
+// sitemap 中的页面类型
+const (
+	SitemapArticle  = "article"
+	SitemapCategory = "category"
+	SitemapAuthor   = "author"
+)
+
+// SitemapEntry sitemap 中的一个页面
+type SitemapEntry struct {
+	Kind      string
+	ID        uint
+	Slug      string
+	UpdatedAt time.Time
+}
+
+// sitemapCache 缓存 sitemap 的页面列表，避免抓取 sitemap 索引的各部分时反复查询全部页面
+var sitemapCache struct {
+	sync.Mutex
+	entries []SitemapEntry
+	expires time.Time
+}
+
+// invalidateSitemap 清空 sitemap 缓存，在文章变化后调用
+func invalidateSitemap() {
+	sitemapCache.Lock()
+	sitemapCache.entries = nil
+	sitemapCache.Unlock()
+}
+
+// GetSitemapEntries 获取需要出现在 sitemap 中的页面：已发布的文章、分类和有已发布文章的作者
+//
+// 结果缓存 site.sitemap_cache_ttl 秒，文章变化时立即失效；返回的切片由多个请求共享，不能修改。
+func GetSitemapEntries() ([]SitemapEntry, error) {
+	sitemapCache.Lock()
+	defer sitemapCache.Unlock()
+
+	if sitemapCache.entries != nil && time.Now().Before(sitemapCache.expires) {
+		return sitemapCache.entries, nil
+	}
+
+	entries, err := loadSitemapEntries()
+	if err != nil {
+		return nil, err
+	}
+	sitemapCache.entries = entries
+	sitemapCache.expires = time.Now().Add(time.Duration(utils.SiteSitemapTTL) * time.Second)
+	return entries, nil
+}
+
+func loadSitemapEntries() ([]SitemapEntry, error) {
+	var articles []SitemapEntry
+	if err := db.Model(&Article{}).Scopes(listedScope).
+		Select("id, slug, updated_at").
+		Order("id").
+		Scan(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	var categories []SitemapEntry
+	if err := db.Model(&Category{}).
+		Select("id, slug, updated_at").
+		Order("id").
+		Scan(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	var authors []SitemapEntry
+	if err := db.Model(&User{}).
+		Select("id, updated_at").
+		Where("is_active = ? AND id IN (?)", true,
+			db.Model(&Article{}).Scopes(listedScope).Select("user_id")).
+		Order("id").
+		Scan(&authors).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]SitemapEntry, 0, len(articles)+len(categories)+len(authors))
+	for _, group := range []struct {
+		kind  string
+		items []SitemapEntry
+	}{
+		{SitemapArticle, articles},
+		{SitemapCategory, categories},
+		{SitemapAuthor, authors},
+	} {
+		for _, item := range group.items {
+			item.Kind = group.kind
+			entries = append(entries, item)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"github.com/HauKuen/Annals/internal/utils/slug"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// slug 所属的对象类型
+const (
+	SlugKindArticle  = "article"
+	SlugKindCategory = "category"
+)
+
+// SlugRedirect 记录变更前的 slug，用于将旧链接重定向到新地址
+type SlugRedirect struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Kind      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_kind_slug" json:"kind"`
+	Slug      string    `gorm:"type:varchar(120);not null;uniqueIndex:idx_kind_slug" json:"slug"`
+	TargetID  uint      `gorm:"not null;index" json:"target_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// slugTables slug 类型对应的数据表
+var slugTables = map[string]string{
+	SlugKindArticle:  "article",
+	SlugKindCategory: "category",
+}
+
+// makeSlug 根据文本生成 slug，无法生成时使用配置的前缀加随机字符
+func makeSlug(text string) string {
+	s := slug.Make(text, slug.Options{
+		Pinyin:    utils.SlugPinyin,
+		MaxLength: utils.SlugMaxLength,
+	})
+	if s != "" {
+		return s
+	}
+
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%s-%d", utils.SlugFallback, time.Now().UnixNano())
+	}
+	return utils.SlugFallback + "-" + hex.EncodeToString(buf)
+}
+
+// uniqueSlug 在 base 后追加序号直到不与其他记录冲突
+func uniqueSlug(tx *gorm.DB, kind string, base string, excludeID uint) (string, error) {
+	candidate := base
+	for i := 2; ; i++ {
+		var count int64
+		if err := tx.Unscoped().Table(slugTables[kind]).
+			Where("slug = ? AND id != ?", candidate, excludeID).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// checkSlug 校验作者指定的 slug 格式及是否被占用
+func checkSlug(kind string, s string, excludeID uint) int {
+	if !slug.Valid(s) || len(s) > 120 {
+		return respcode.ErrorSlugInvalid
+	}
+
+	var count int64
+	if err := db.Unscoped().Table(slugTables[kind]).
+		Where("slug = ? AND id != ?", s, excludeID).
+		Count(&count).Error; err != nil {
+		return respcode.ERROR
+	}
+	if count > 0 {
+		return respcode.ErrorSlugUsed
+	}
+	return respcode.SUCCESS
+}
+
+// recordSlugChange 记录旧 slug 的重定向，新 slug 若曾是该对象的旧 slug 则移除该记录
+func recordSlugChange(tx *gorm.DB, kind string, targetID uint, oldSlug string, newSlug string) error {
+	if err := tx.Where("kind = ? AND slug = ?", kind, newSlug).Delete(&SlugRedirect{}).Error; err != nil {
+		return err
+	}
+	if oldSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"target_id", "created_at"}),
+	}).Create(&SlugRedirect{Kind: kind, Slug: oldSlug, TargetID: targetID}).Error
+}
+
+// resolveSlug 通过 slug 查找对象ID，返回的 current 为对象当前的 slug
+func resolveSlug(kind string, s string) (uint, string, int) {
+	var row struct {
+		ID   uint
+		Slug string
+	}
+
+	err := db.Table(slugTables[kind]).Select("id, slug").
+		Where("slug = ? AND deleted_at IS NULL", s).
+		Take(&row).Error
+	if err == nil {
+		return row.ID, row.Slug, respcode.SUCCESS
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, "", respcode.ERROR
+	}
+
+	// 在历史记录中查找
+	var redirect SlugRedirect
+	if err := db.Where("kind = ? AND slug = ?", kind, s).First(&redirect).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", respcode.NotFound
+		}
+		return 0, "", respcode.ERROR
+	}
+
+	err = db.Table(slugTables[kind]).Select("id, slug").
+		Where("id = ? AND deleted_at IS NULL", redirect.TargetID).
+		Take(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", respcode.NotFound
+		}
+		return 0, "", respcode.ERROR
+	}
+	return row.ID, row.Slug, respcode.SUCCESS
+}
+
+// ResolveArticleSlug 通过 slug 查找文章ID及其当前 slug
+func ResolveArticleSlug(s string) (uint, string, int) {
+	id, current, code := resolveSlug(SlugKindArticle, s)
+	if code == respcode.NotFound {
+		code = respcode.ErrorArtNotExist
+	}
+	return id, current, code
+}
+
+// ResolveCategorySlug 通过 slug 查找分类ID及其当前 slug
+func ResolveCategorySlug(s string) (uint, string, int) {
+	id, current, code := resolveSlug(SlugKindCategory, s)
+	if code == respcode.NotFound {
+		code = respcode.ErrorCateNotExist
+	}
+	return id, current, code
+}
+
+// backfillSlugs 为 slug 功能上线前创建的文章和分类生成 slug
+func backfillSlugs() error {
+	var articles []Article
+	if err := db.Unscoped().Select("id", "title").
+		Where("slug IS NULL OR slug = ''").
+		Find(&articles).Error; err != nil {
+		return err
+	}
+	for _, article := range articles {
+		s, err := uniqueSlug(db, SlugKindArticle, makeSlug(article.Title), article.ID)
+		if err != nil {
+			return err
+		}
+		if err := db.Unscoped().Model(&Article{}).Where("id = ?", article.ID).
+			UpdateColumn("slug", s).Error; err != nil {
+			return err
+		}
+	}
+
+	var categories []Category
+	if err := db.Unscoped().Select("id", "name").
+		Where("slug IS NULL OR slug = ''").
+		Find(&categories).Error; err != nil {
+		return err
+	}
+	for _, category := range categories {
+		s, err := uniqueSlug(db, SlugKindCategory, makeSlug(category.Name), category.ID)
+		if err != nil {
+			return err
+		}
+		if err := db.Unscoped().Model(&Category{}).Where("id = ?", category.ID).
+			UpdateColumn("slug", s).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"gorm.io/gorm"
+)
+
+// SpamVerdict 垃圾评论检测结果
+type SpamVerdict int
+
+const (
+	// SpamHam 正常评论
+	SpamHam SpamVerdict = iota
+	// SpamSpam 垃圾评论，保存但不展示
+	SpamSpam
+	// SpamBlock 直接拒绝，不保存
+	SpamBlock
+)
+
+// SpamChecker 垃圾评论检测器
+type SpamChecker interface {
+	// Check 检测评论，edit 为 true 时表示修改已有的评论；reason 用于记录日志
+	Check(comment *Comment, edit bool) (verdict SpamVerdict, reason string, err error)
+}
+
+// spamCheckers 可用的检测器，键为配置中的 comment.spam_checker
+var spamCheckers = map[string]func(*gorm.DB) (SpamChecker, error){
+	"heuristic": newHeuristicChecker,
+	"none":      func(*gorm.DB) (SpamChecker, error) { return nil, nil },
+}
+
+var spamChecker SpamChecker
+
+// RegisterSpamChecker 注册自定义垃圾评论检测器，需在 InitDb 之前调用
+func RegisterSpamChecker(name string, factory func(*gorm.DB) (SpamChecker, error)) {
+	spamCheckers[name] = factory
+}
+
+// initSpamChecker 根据配置初始化垃圾评论检测器
+func initSpamChecker() error {
+	factory, ok := spamCheckers[utils.CommentSpamChecker]
+	if !ok {
+		return fmt.Errorf("未知的垃圾评论检测器: %s", utils.CommentSpamChecker)
+	}
+
+	checker, err := factory(db)
+	if err != nil {
+		return err
+	}
+	spamChecker = checker
+	return nil
+}
+
+// checkSpam 使用配置的检测器检测评论，检测出错时按正常评论处理，交由人工审核
+func checkSpam(comment *Comment, edit bool) SpamVerdict {
+	if spamChecker == nil {
+		return SpamHam
+	}
+
+	verdict, reason, err := spamChecker.Check(comment, edit)
+	if err != nil {
+		utils.Log.Error("垃圾评论检测失败:", err)
+		return SpamHam
+	}
+	if verdict != SpamHam {
+		utils.Log.Infof("评论被判定为垃圾评论 (用户 %d, IP %s): %s", comment.UserID, comment.IP, reason)
+	}
+	return verdict
+}
+
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// heuristicChecker 内置的规则检测器：链接数量、屏蔽词、重复内容和单 IP 发表频率
+type heuristicChecker struct {
+	db *gorm.DB
+}
+
+func newHeuristicChecker(db *gorm.DB) (SpamChecker, error) {
+	return &heuristicChecker{db: db}, nil
+}
+
+func (h *heuristicChecker) Check(comment *Comment, edit bool) (SpamVerdict, string, error) {
+	// 修改评论不计入发表频率
+	if !edit && utils.CommentRateLimit > 0 && comment.IP != "" {
+		var recent int64
+		since := time.Now().Add(-time.Duration(utils.CommentRateWindow) * time.Minute)
+		if err := h.db.Model(&Comment{}).
+			Where("ip = ? AND created_at > ?", comment.IP, since).
+			Count(&recent).Error; err != nil {
+			return SpamHam, "", err
+		}
+		if recent >= int64(utils.CommentRateLimit) {
+			return SpamBlock, "发表过于频繁", nil
+		}
+	}
+
+	if links := len(linkPattern.FindAllStringIndex(comment.Content, -1)); links > utils.CommentMaxLinks {
+		return SpamSpam, fmt.Sprintf("包含 %d 个链接", links), nil
+	}
+
+	lower := strings.ToLower(comment.Content)
+	for _, word := range utils.CommentBlockedWords {
+		if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+			return SpamSpam, "包含屏蔽词 " + word, nil
+		}
+	}
+
+	if utils.CommentDuplicateWindow > 0 {
+		var duplicates int64
+		since := time.Now().Add(-time.Duration(utils.CommentDuplicateWindow) * time.Minute)
+		query := h.db.Model(&Comment{}).
+			Where("content = ? AND created_at > ?", comment.Content, since)
+		if comment.IP != "" {
+			query = query.Where("user_id = ? OR ip = ?", comment.UserID, comment.IP)
+		} else {
+			query = query.Where("user_id = ?", comment.UserID)
+		}
+		if edit {
+			query = query.Where("id <> ?", comment.ID)
+		}
+		if err := query.Count(&duplicates).Error; err != nil {
+			return SpamHam, "", err
+		}
+		if duplicates > 0 {
+			return SpamSpam, "重复内容", nil
+		}
+	}
+
+	return SpamHam, "", nil
+}
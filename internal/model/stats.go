@@ -0,0 +1,202 @@
+package model
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+)
+
+// 统计中排行榜的长度和新用户趋势的周数
+const (
+	statsTopLimit = 10
+	statsWeeks    = 12
+)
+
+// DashboardStats 管理后台的统计数据
+type DashboardStats struct {
+	Totals             StatsTotals     `json:"totals"`
+	ArticlesByStatus   []StatusCount   `json:"articles_by_status"`
+	ArticlesByCategory []CategoryCount `json:"articles_by_category"`
+	NewUsersPerWeek    []WeeklyCount   `json:"new_users_per_week"`
+	TopAuthors         []AuthorStats   `json:"top_authors"`
+	TopArticles        []ArticleViews  `json:"top_articles"`
+	// 数据库占用的空间，单位为字节
+	DatabaseSize int64     `json:"database_size"`
+	GeneratedAt  time.Time `json:"generated_at"`
+}
+
+// StatsTotals 各类数据的总数
+type StatsTotals struct {
+	Articles   int64 `json:"articles"`
+	Users      int64 `json:"users"`
+	Comments   int64 `json:"comments"`
+	Categories int64 `json:"categories"`
+	Tags       int64 `json:"tags"`
+	Views      int64 `json:"views"`
+}
+
+// StatusCount 某个状态下的文章数量
+type StatusCount struct {
+	Status int   `json:"status"`
+	Count  int64 `json:"count"`
+}
+
+// CategoryCount 某个分类下的文章数量
+type CategoryCount struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Count      int64  `json:"count"`
+}
+
+// WeeklyCount 某一周的数量，Week 为该周周一的日期
+type WeeklyCount struct {
+	Week  string `json:"week"`
+	Count int64  `json:"count"`
+}
+
+// AuthorStats 作者已发布的文章数和总访问量
+type AuthorStats struct {
+	ID          uint   `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Articles    int64  `json:"articles"`
+	Views       int64  `json:"views"`
+}
+
+// ArticleViews 文章及其访问量
+type ArticleViews struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	ViewCount int64  `json:"view_count"`
+}
+
+// dashboardCache 缓存统计结果，避免频繁执行聚合查询
+var dashboardCache struct {
+	sync.Mutex
+	stats   *DashboardStats
+	expires time.Time
+}
+
+// GetDashboardStats 获取管理后台统计数据，结果缓存 stats.cache_ttl 秒
+func GetDashboardStats() (*DashboardStats, int) {
+	dashboardCache.Lock()
+	defer dashboardCache.Unlock()
+
+	if dashboardCache.stats != nil && time.Now().Before(dashboardCache.expires) {
+		return dashboardCache.stats, respcode.SUCCESS
+	}
+
+	stats, err := collectDashboardStats()
+	if err != nil {
+		utils.Log.Error("统计数据查询失败:", err)
+		return nil, respcode.ERROR
+	}
+
+	dashboardCache.stats = stats
+	dashboardCache.expires = time.Now().Add(time.Duration(utils.StatsCacheTTL) * time.Second)
+	return stats, respcode.SUCCESS
+}
+
+func collectDashboardStats() (*DashboardStats, error) {
+	stats := &DashboardStats{GeneratedAt: time.Now()}
+
+	totals := []struct {
+		model interface{}
+		count *int64
+	}{
+		{&Article{}, &stats.Totals.Articles},
+		{&User{}, &stats.Totals.Users},
+		{&Comment{}, &stats.Totals.Comments},
+		{&Category{}, &stats.Totals.Categories},
+		{&Tag{}, &stats.Totals.Tags},
+	}
+	for _, t := range totals {
+		if err := db.Model(t.model).Count(t.count).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := db.Model(&Article{}).Select("COALESCE(SUM(view_count), 0)").Scan(&stats.Totals.Views).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&Article{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Order("status").
+		Scan(&stats.ArticlesByStatus).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&Article{}).
+		Select("article.category_id, COALESCE(category.name, '') AS name, COUNT(*) AS count").
+		Joins("LEFT JOIN category ON category.id = article.category_id AND category.deleted_at IS NULL").
+		Group("article.category_id, category.name").
+		Order("count DESC").
+		Scan(&stats.ArticlesByCategory).Error; err != nil {
+		return nil, err
+	}
+
+	weeks, err := newUsersPerWeek()
+	if err != nil {
+		return nil, err
+	}
+	stats.NewUsersPerWeek = weeks
+
+	if err := db.Model(&Article{}).Scopes(publishedScope).
+		Select("`user`.id, `user`.username, `user`.display_name, COUNT(*) AS articles, SUM(article.view_count) AS views").
+		Joins("JOIN `user` ON `user`.id = article.user_id").
+		Group("`user`.id, `user`.username, `user`.display_name").
+		Order("articles DESC, views DESC").
+		Limit(statsTopLimit).
+		Scan(&stats.TopAuthors).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&Article{}).Scopes(publishedScope).
+		Select("id, title, slug, view_count").
+		Order("view_count DESC, id").
+		Limit(statsTopLimit).
+		Scan(&stats.TopArticles).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Raw("SELECT COALESCE(SUM(data_length + index_length), 0) FROM information_schema.tables WHERE table_schema = DATABASE()").
+		Scan(&stats.DatabaseSize).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// newUsersPerWeek 统计最近若干周每周的新用户数，没有新用户的周计为 0
+func newUsersPerWeek() ([]WeeklyCount, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// 本周周一
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	start := monday.AddDate(0, 0, -7*(statsWeeks-1))
+
+	var rows []WeeklyCount
+	if err := db.Model(&User{}).
+		Select("DATE_FORMAT(DATE_SUB(DATE(created_at), INTERVAL WEEKDAY(created_at) DAY), '%Y-%m-%d') AS week, COUNT(*) AS count").
+		Where("created_at >= ?", start).
+		Group("week").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Week] = row.Count
+	}
+
+	weeks := make([]WeeklyCount, statsWeeks)
+	for i := range weeks {
+		week := start.AddDate(0, 0, 7*i).Format(time.DateOnly)
+		weeks[i] = WeeklyCount{Week: week, Count: counts[week]}
+	}
+	return weeks, nil
+}
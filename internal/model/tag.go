@@ -0,0 +1,223 @@
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Tag struct {
+	gorm.Model
+	Name string `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+}
+
+// TagCount 标签及其已发布文章数量，用于标签云
+type TagCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// CreateTag 创建标签
+func CreateTag(data *Tag) int {
+	tags, err := findOrCreateTags(db, []string{data.Name})
+	if err != nil {
+		utils.Log.Error("创建标签失败:", err)
+		return respcode.ERROR
+	}
+	if len(tags) == 0 {
+		return respcode.ErrorEmptyTagName
+	}
+	*data = tags[0]
+	return respcode.SUCCESS
+}
+
+// CheckTag 查询标签是否存在
+func CheckTag(name string) int {
+	var tag Tag
+	if err := db.Select("id").Where("name = ?", name).First(&tag).Error; err != nil {
+		return respcode.ErrorTagNotExist
+	}
+	return respcode.SUCCESS
+}
+
+// GetTag 查询标签
+func GetTag(id int) (Tag, int) {
+	var tag Tag
+	if err := db.Where("id = ?", id).First(&tag).Error; err != nil {
+		return tag, respcode.ErrorTagNotExist
+	}
+	return tag, respcode.SUCCESS
+}
+
+// GetTags 获取所有标签
+func GetTags() ([]Tag, int) {
+	var tags []Tag
+	if err := db.Order("name").Find(&tags).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+	return tags, respcode.SUCCESS
+}
+
+// EditTag 重命名标签
+func EditTag(id int, name string) int {
+	var tag Tag
+	if err := db.First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return respcode.ErrorTagNotExist
+		}
+		return respcode.ERROR
+	}
+
+	// 已删除的标签仍占用唯一索引
+	var count int64
+	db.Unscoped().Model(&Tag{}).Where("name = ? AND id != ?", name, id).Count(&count)
+	if count > 0 {
+		return respcode.ErrorTagNameUsed
+	}
+
+	if err := db.Model(&tag).Update("name", name).Error; err != nil {
+		return respcode.ERROR
+	}
+
+	tagChanged(tag.ID)
+	return respcode.SUCCESS
+}
+
+// DeleteTag 删除标签及其与文章的关联
+func DeleteTag(id int) int {
+	var tag Tag
+	if err := db.First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return respcode.ErrorTagNotExist
+		}
+		return respcode.ERROR
+	}
+
+	// 删除关联前记录受影响的文章
+	articleIDs := taggedArticleIDs(tag.ID)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("article_tag").Where("tag_id = ?", id).Delete(nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+	if err != nil {
+		utils.Log.Error("删除标签失败:", err)
+		return respcode.ERROR
+	}
+
+	articleChanged(articleIDs...)
+	return respcode.SUCCESS
+}
+
+// tagChanged 标签变更后更新使用该标签的文章
+func tagChanged(tagID uint) {
+	articleChanged(taggedArticleIDs(tagID)...)
+}
+
+// taggedArticleIDs 获取使用了标签的文章ID
+func taggedArticleIDs(tagID uint) []uint {
+	var ids []uint
+	if err := db.Table("article_tag").Where("tag_id = ?", tagID).Pluck("article_id", &ids).Error; err != nil {
+		utils.Log.Error("查询标签文章失败:", err)
+	}
+	return ids
+}
+
+// GetTagCloud 获取已发布文章最多的标签
+func GetTagCloud(limit int) ([]TagCount, int) {
+	var counts []TagCount
+	err := db.Model(&Tag{}).
+		Select("tag.id, tag.name, COUNT(article.id) AS count").
+		Joins("JOIN article_tag ON article_tag.tag_id = tag.id").
+		Joins("JOIN article ON article.id = article_tag.article_id AND article.deleted_at IS NULL").
+		Scopes(listedScope).
+		Group("tag.id, tag.name").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, respcode.ERROR
+	}
+	return counts, respcode.SUCCESS
+}
+
+// GetArticlesByTag 获取标签下已发布的文章，排序和置顶规则与文章列表相同
+func GetArticlesByTag(tagID int, page *pagination.Page) ([]Article, int64, int) {
+	var total int64
+
+	// 检查标签是否存在
+	var tag Tag
+	if err := db.First(&tag, tagID).Error; err != nil {
+		return nil, 0, respcode.ErrorTagNotExist
+	}
+
+	q := &ArticleQuery{}
+	if code := q.validatePage(page); code != respcode.SUCCESS {
+		return nil, 0, code
+	}
+	byTag := func(tx *gorm.DB) *gorm.DB {
+		return tx.Joins("JOIN article_tag ON article_tag.article_id = article.id").
+			Where("article_tag.tag_id = ?", tagID)
+	}
+
+	if !page.UseCursor {
+		db.Model(&Article{}).Scopes(q.filter(false), byTag).Count(&total)
+	}
+	articles, err := findArticleList(q, page, byTag)
+	if err != nil {
+		return nil, 0, respcode.ERROR
+	}
+
+	fillArticleExtras(articles)
+	return articles, total, respcode.SUCCESS
+}
+
+// setArticleTags 将文章的标签替换为给定名称的标签，不存在的标签会自动创建
+func setArticleTags(tx *gorm.DB, article *Article, names []string) error {
+	tags, err := findOrCreateTags(tx, names)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		return tx.Model(article).Association("Tags").Clear()
+	}
+	return tx.Model(article).Association("Tags").Replace(tags)
+}
+
+// findOrCreateTags 按名称查找标签，不存在时创建
+func findOrCreateTags(tx *gorm.DB, names []string) ([]Tag, error) {
+	var tags []Tag
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		// 依赖唯一索引处理并发创建，已被软删除的同名标签会被恢复
+		if err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]interface{}{"deleted_at": nil}),
+		}).Create(&Tag{Name: name}).Error; err != nil {
+			return nil, err
+		}
+
+		var tag Tag
+		if err := tx.Where("name = ?", name).First(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/pagination"
 	"github.com/HauKuen/Annals/internal/utils/respcode"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -39,6 +40,29 @@ type APIUser struct {
 	IsActive    bool   `json:"is_active"`
 }
 
+// Author 文章中展示的作者信息，不包含邮箱、密码等敏感字段
+type Author struct {
+	ID          uint   `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+func (Author) TableName() string {
+	return "user"
+}
+
+// AuthorProfile 公开的作者主页信息
+type AuthorProfile struct {
+	ID           uint      `json:"id"`
+	Username     string    `json:"username"`
+	DisplayName  string    `json:"display_name"`
+	Bio          string    `json:"bio"`
+	AvatarURL    string    `json:"avatar_url"`
+	CreatedAt    time.Time `json:"created_at"`
+	ArticleCount int64     `json:"article_count"`
+}
+
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
@@ -78,14 +102,31 @@ func GetUser(id int) (APIUser, int) {
 	return apiUser, respcode.SUCCESS
 }
 
+// GetAuthorProfile 查询公开的作者信息，已停用的用户视为不存在
+func GetAuthorProfile(id int) (AuthorProfile, int) {
+	var profile AuthorProfile
+
+	err := db.Model(&User{}).Where("is_active = ?", true).First(&profile, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return profile, respcode.ErrorUserNotExist
+		}
+		return profile, respcode.ERROR
+	}
+
+	db.Model(&Article{}).Scopes(listedScope).Where("user_id = ?", id).Count(&profile.ArticleCount)
+	return profile, respcode.SUCCESS
+}
+
 // GetUsers 查询用户列表
-func GetUsers(pageSize int, pageNum int) ([]APIUser, int64) {
+func GetUsers(page *pagination.Page) ([]APIUser, int64) {
 	var users []APIUser
 	var total int64
-	offset := (pageNum - 1) * pageSize
-	db.Model(&User{}).Limit(pageSize).Offset(offset).Find(&users)
-	db.Model(&User{}).Count(&total)
-	return users, total
+	db.Model(&User{}).Scopes(page.Scope("`user`.created_at", "`user`.id")).Find(&users)
+	if !page.UseCursor {
+		db.Model(&User{}).Count(&total)
+	}
+	return pagination.Paginate(page, users, userKey), total
 }
 
 // CreateUser 添加用户
@@ -0,0 +1,277 @@
+package model
+
+import (
+	"hash/fnv"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ArticleViewDaily 文章每日访问量
+type ArticleViewDaily struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false"`
+	Date      time.Time `gorm:"primaryKey;type:date"`
+	Views     int64     `gorm:"not null;default:0"`
+}
+
+// ArticleReferrer 文章每日来源站点的访问量
+type ArticleReferrer struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false"`
+	Date      time.Time `gorm:"primaryKey;type:date"`
+	Host      string    `gorm:"primaryKey;type:varchar(191)"`
+	Views     int64     `gorm:"not null;default:0"`
+}
+
+// DailyViews 访问量时间线中的一天
+type DailyViews struct {
+	Date  string `json:"date"`
+	Views int64  `json:"views"`
+}
+
+// ReferrerViews 来源站点及其带来的访问量
+type ReferrerViews struct {
+	Host  string `json:"host"`
+	Views int64  `json:"views"`
+}
+
+type viewKey struct {
+	articleID uint
+	date      string
+}
+
+type referrerKey struct {
+	articleID uint
+	date      string
+	host      string
+}
+
+// viewBuffer 内存中尚未写入数据库的访问计数
+//
+// 访问文章时只修改内存，由 FlushArticleViews 定期批量写入，避免读请求变成写请求。
+var viewBuffer = struct {
+	sync.Mutex
+	views     map[viewKey]int64
+	referrers map[referrerKey]int64
+	// 访客最近一次被计数的时间，用于去重，键为访客和文章ID的哈希
+	seen map[uint64]time.Time
+}{
+	views:     make(map[viewKey]int64),
+	referrers: make(map[referrerKey]int64),
+	seen:      make(map[uint64]time.Time),
+}
+
+// viewSeenLimit 去重记录的最大数量，避免不断更换 User-Agent 的访客在两次写入之间耗尽内存
+const viewSeenLimit = 100000
+
+// pruneSeenViews 清理已过去重窗口的访客记录，调用时需持有 viewBuffer 的锁
+func pruneSeenViews(now time.Time) {
+	expire := now.Add(-viewDedupeWindow())
+	for key, last := range viewBuffer.seen {
+		if last.Before(expire) {
+			delete(viewBuffer.seen, key)
+		}
+	}
+}
+
+// viewFlushMu 保证同一时间只有一次写入，退出前的最后一次写入会等待定时写入完成
+var viewFlushMu sync.Mutex
+
+// RecordView 记录一次文章访问
+//
+// visitor 用于标识访客，同一访客在去重时间窗口内重复访问只计一次；
+// 爬虫的访问不计数，来自本站 selfHost 的来源不记录。
+// 去重记录达到 viewSeenLimit 时先清理过期的记录，仍然接近上限则全部清空，此后的重复访问可能被多计一次。
+func RecordView(articleID uint, visitor string, userAgent string, referer string, selfHost string) {
+	if isBot(userAgent) {
+		return
+	}
+
+	now := time.Now()
+	h := fnv.New64a()
+	h.Write([]byte(visitor + "#" + strconv.FormatUint(uint64(articleID), 10)))
+	key := h.Sum64()
+	date := now.Format(time.DateOnly)
+	host := referrerHost(referer, selfHost)
+
+	viewBuffer.Lock()
+	defer viewBuffer.Unlock()
+
+	if last, ok := viewBuffer.seen[key]; ok && now.Sub(last) < viewDedupeWindow() {
+		return
+	}
+	if len(viewBuffer.seen) >= viewSeenLimit {
+		pruneSeenViews(now)
+		// 清理后仍接近上限时全部清空，避免每次访问都遍历整个记录
+		if len(viewBuffer.seen) >= viewSeenLimit*9/10 {
+			viewBuffer.seen = make(map[uint64]time.Time)
+		}
+	}
+	viewBuffer.seen[key] = now
+
+	viewBuffer.views[viewKey{articleID, date}]++
+	if host != "" {
+		viewBuffer.referrers[referrerKey{articleID, date, host}]++
+	}
+}
+
+// FlushArticleViews 将内存中的访问计数写入数据库，返回写入的访问次数
+//
+// 写入失败时计数会放回内存，在下次刷新时重试。
+func FlushArticleViews() (int64, error) {
+	viewFlushMu.Lock()
+	defer viewFlushMu.Unlock()
+
+	viewBuffer.Lock()
+	views, referrers := viewBuffer.views, viewBuffer.referrers
+	viewBuffer.views = make(map[viewKey]int64)
+	viewBuffer.referrers = make(map[referrerKey]int64)
+
+	pruneSeenViews(time.Now())
+	viewBuffer.Unlock()
+
+	if len(views) == 0 {
+		return 0, nil
+	}
+
+	var total int64
+	totals := make(map[uint]int64)
+	for key, n := range views {
+		totals[key.articleID] += n
+		total += n
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for id, n := range totals {
+			// 使用 UpdateColumn 避免访问计数修改文章的 updated_at
+			if err := tx.Model(&Article{}).Where("id = ?", id).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error; err != nil {
+				return err
+			}
+		}
+
+		for key, n := range views {
+			row := ArticleViewDaily{ArticleID: key.articleID, Date: parseViewDate(key.date), Views: n}
+			if err := tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("views + ?", n)}),
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+
+		for key, n := range referrers {
+			row := ArticleReferrer{ArticleID: key.articleID, Date: parseViewDate(key.date), Host: key.host, Views: n}
+			if err := tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("views + ?", n)}),
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		viewBuffer.Lock()
+		for key, n := range views {
+			viewBuffer.views[key] += n
+		}
+		for key, n := range referrers {
+			viewBuffer.referrers[key] += n
+		}
+		viewBuffer.Unlock()
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// GetArticleViewTimeline 获取文章最近 days 天每天的访问量，没有访问的日期计为 0
+func GetArticleViewTimeline(articleID int, days int) ([]DailyViews, int) {
+	start := time.Now().AddDate(0, 0, 1-days)
+
+	var rows []ArticleViewDaily
+	if err := db.Where("article_id = ? AND date >= ?", articleID, start.Format(time.DateOnly)).
+		Find(&rows).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Date.Format(time.DateOnly)] = row.Views
+	}
+
+	timeline := make([]DailyViews, days)
+	for i := range timeline {
+		date := start.AddDate(0, 0, i).Format(time.DateOnly)
+		timeline[i] = DailyViews{Date: date, Views: counts[date]}
+	}
+	return timeline, respcode.SUCCESS
+}
+
+// GetArticleReferrers 获取文章最近 days 天访问量最多的来源站点
+func GetArticleReferrers(articleID int, days int, limit int) ([]ReferrerViews, int) {
+	start := time.Now().AddDate(0, 0, 1-days)
+
+	referrers := []ReferrerViews{}
+	if err := db.Model(&ArticleReferrer{}).
+		Select("host, SUM(views) AS views").
+		Where("article_id = ? AND date >= ?", articleID, start.Format(time.DateOnly)).
+		Group("host").
+		Order("views DESC").
+		Limit(limit).
+		Scan(&referrers).Error; err != nil {
+		return nil, respcode.ERROR
+	}
+	return referrers, respcode.SUCCESS
+}
+
+func viewDedupeWindow() time.Duration {
+	return time.Duration(utils.ViewDedupeWindow) * time.Minute
+}
+
+func parseViewDate(date string) time.Time {
+	t, _ := time.ParseInLocation(time.DateOnly, date, time.Local)
+	return t
+}
+
+// isBot 根据 User-Agent 判断是否为爬虫
+func isBot(userAgent string) bool {
+	if userAgent == "" {
+		return true
+	}
+	userAgent = strings.ToLower(userAgent)
+	for _, agent := range utils.ViewBotAgents {
+		if agent != "" && strings.Contains(userAgent, strings.ToLower(agent)) {
+			return true
+		}
+	}
+	return false
+}
+
+// referrerHost 提取来源站点的主机名，本站和无法解析的来源返回空字符串
+func referrerHost(referer string, selfHost string) string {
+	if referer == "" {
+		return ""
+	}
+	u, err := url.Parse(referer)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+
+	self := selfHost
+	if h, _, err := net.SplitHostPort(selfHost); err == nil {
+		self = h
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if strings.EqualFold(host, self) || len(host) > 191 {
+		return ""
+	}
+	return host
+}
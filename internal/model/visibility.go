@@ -0,0 +1,237 @@
+package model
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 文章可见性，只有公开的文章会出现在列表、搜索、订阅源和站点地图中
+const (
+	ArticleVisibilityPublic   = 1 // 公开
+	ArticleVisibilityUnlisted = 2 // 不公开列出，知道链接即可访问
+	ArticleVisibilityPrivate  = 3 // 私密，只有作者和管理员可见
+	ArticleVisibilityPassword = 4 // 密码保护，输入正确的密码后才能查看正文
+)
+
+func validVisibility(visibility int) bool {
+	return visibility >= ArticleVisibilityPublic && visibility <= ArticleVisibilityPassword
+}
+
+// listedScope 只查询已发布且公开的文章，用于各类文章列表
+func listedScope(tx *gorm.DB) *gorm.DB {
+	return tx.Scopes(publishedScope).Where("article.visibility = ?", ArticleVisibilityPublic)
+}
+
+// accessibleScope 只查询不需要额外凭据即可访问的已发布文章，用于评论、点赞和收藏
+//
+// 访问密码只用于查看正文，解锁不会保存状态，因此密码保护的文章不能评论、点赞和收藏。
+func accessibleScope(tx *gorm.DB) *gorm.DB {
+	return tx.Scopes(publishedScope).
+		Where("article.visibility IN ?", []int{ArticleVisibilityPublic, ArticleVisibilityUnlisted})
+}
+
+// IsListed 文章是否已发布且公开
+func (a *Article) IsListed() bool {
+	return a.IsPublished() && a.Visibility == ArticleVisibilityPublic
+}
+
+// VerifyPassword 验证文章的访问密码，未设置密码保护的文章总是返回 true
+func (a *Article) VerifyPassword(password string) bool {
+	if a.Visibility != ArticleVisibilityPassword {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(password)) == nil
+}
+
+// unlockWindow 统计输错访问密码次数的时间窗口
+func unlockWindow() time.Duration {
+	return time.Duration(utils.UnlockWindow) * time.Minute
+}
+
+type unlockKey struct {
+	articleID uint
+	client    string
+}
+
+// unlockFailure 一个时间窗口内输错访问密码的次数
+type unlockFailure struct {
+	start time.Time
+	count int
+}
+
+// unlockFailures 内存中的访问密码错误计数，分别按文章和按文章及客户端 IP 统计；
+// unlocked 记录窗口内成功解锁过文章的客户端
+var unlockFailures = struct {
+	sync.Mutex
+	byArticle map[uint]*unlockFailure
+	byClient  map[unlockKey]*unlockFailure
+	unlocked  map[unlockKey]time.Time
+}{
+	byArticle: make(map[uint]*unlockFailure),
+	byClient:  make(map[unlockKey]*unlockFailure),
+	unlocked:  make(map[unlockKey]time.Time),
+}
+
+// failures 返回窗口内的错误次数，窗口已过期时返回 0
+func (f *unlockFailure) failures(now time.Time) int {
+	if f == nil || now.Sub(f.start) >= unlockWindow() {
+		return 0
+	}
+	return f.count
+}
+
+// addUnlockFailure 将计数加上 delta，窗口已过期时开始新的窗口
+func addUnlockFailure(f *unlockFailure, now time.Time, delta int) *unlockFailure {
+	if f.failures(now) == 0 {
+		f = &unlockFailure{start: now}
+	}
+	f.count += delta
+	return f
+}
+
+// pruneUnlockFailures 清除已过期的记录，调用时需持有锁
+func pruneUnlockFailures(now time.Time) {
+	for k, f := range unlockFailures.byClient {
+		if f.failures(now) == 0 {
+			delete(unlockFailures.byClient, k)
+		}
+	}
+	for k, f := range unlockFailures.byArticle {
+		if f.failures(now) == 0 {
+			delete(unlockFailures.byArticle, k)
+		}
+	}
+	for k, t := range unlockFailures.unlocked {
+		if now.Sub(t) >= unlockWindow() {
+			delete(unlockFailures.unlocked, k)
+		}
+	}
+}
+
+// UnlockArticle 验证密码保护文章的访问密码，client 为访客的 IP
+//
+// 同一访客在 unlock.window 分钟内输错的次数达到 unlock.client_limit 后，在窗口结束前拒绝验证；
+// 验证前先计入一次错误，密码正确时再撤销，避免并发的请求绕过上限。
+// 所有访客输错的次数超过 unlock.article_limit 后不拒绝验证，只让窗口内没有解锁过的访客
+// 每次验证前等待 unlock.article_delay 秒，减慢换 IP 的猜测，又不会把知道密码的读者挡在外面。
+func UnlockArticle(article *Article, password string, client string) int {
+	key := unlockKey{article.ID, client}
+	now := time.Now()
+
+	unlockFailures.Lock()
+	if utils.UnlockClientLimit > 0 && unlockFailures.byClient[key].failures(now) >= utils.UnlockClientLimit {
+		unlockFailures.Unlock()
+		return respcode.ErrorArtUnlockTooMany
+	}
+	pruneUnlockFailures(now)
+	_, trusted := unlockFailures.unlocked[key]
+	slow := !trusted && utils.UnlockArticleLimit > 0 &&
+		unlockFailures.byArticle[article.ID].failures(now) >= utils.UnlockArticleLimit
+	unlockFailures.byClient[key] = addUnlockFailure(unlockFailures.byClient[key], now, 1)
+	unlockFailures.Unlock()
+
+	if slow {
+		time.Sleep(time.Duration(utils.UnlockArticleDelay) * time.Second)
+	}
+
+	if !article.VerifyPassword(password) {
+		unlockFailures.Lock()
+		unlockFailures.byArticle[article.ID] = addUnlockFailure(unlockFailures.byArticle[article.ID], time.Now(), 1)
+		unlockFailures.Unlock()
+		return respcode.ErrorArtPasswordWrong
+	}
+
+	unlockFailures.Lock()
+	if f := unlockFailures.byClient[key]; f != nil && f.count > 0 {
+		f.count--
+	}
+	unlockFailures.unlocked[key] = time.Now()
+	unlockFailures.Unlock()
+	return respcode.SUCCESS
+}
+
+// HideContent 隐藏密码保护文章的正文及由正文生成的字段
+func (a *Article) HideContent() {
+	a.Content = ""
+	a.ContentHTML = ""
+	a.Excerpt = ""
+	a.TOC = nil
+	a.Locked = true
+}
+
+// HideComments 隐藏密码保护文章的评论数量，这类文章的评论只对作者和管理员可见
+func (a *Article) HideComments() {
+	a.CommentCount = 0
+}
+
+// applyVisibility 检查新文章的可见性，密码保护时将提交的密码加密保存
+func (a *Article) applyVisibility() int {
+	if a.Visibility == 0 {
+		a.Visibility = ArticleVisibilityPublic
+	}
+	if !validVisibility(a.Visibility) {
+		return respcode.ErrorArtVisibilityInvalid
+	}
+
+	a.PasswordHash = ""
+	if a.Visibility == ArticleVisibilityPassword {
+		if a.Password == "" {
+			return respcode.ErrorArtPasswordEmpty
+		}
+		hash, err := hashArticlePassword(a.Password)
+		if err != nil {
+			return respcode.ERROR
+		}
+		a.PasswordHash = hash
+	}
+	a.Password = ""
+	return respcode.SUCCESS
+}
+
+// visibilityUpdates 将可见性和访问密码的修改写入 updates
+//
+// 只修改密码时沿用原有的可见性；改为其他可见性时清除密码；
+// 改为密码保护时必须提交密码，除非文章原本已设置了密码。
+func visibilityUpdates(existing *Article, article *Article, updates map[string]interface{}) int {
+	if article.Visibility == 0 && article.Password == "" {
+		return respcode.SUCCESS
+	}
+
+	visibility := article.Visibility
+	if visibility == 0 {
+		visibility = existing.Visibility
+	}
+	if !validVisibility(visibility) {
+		return respcode.ErrorArtVisibilityInvalid
+	}
+	updates["visibility"] = visibility
+
+	switch {
+	case visibility != ArticleVisibilityPassword:
+		updates["password_hash"] = ""
+	case article.Password != "":
+		hash, err := hashArticlePassword(article.Password)
+		if err != nil {
+			return respcode.ERROR
+		}
+		updates["password_hash"] = hash
+	case existing.PasswordHash == "":
+		return respcode.ErrorArtPasswordEmpty
+	}
+	article.Password = ""
+	return respcode.SUCCESS
+}
+
+func hashArticlePassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		utils.Log.Error("加密文章密码失败:", err)
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/HauKuen/Annals/internal/utils"
+	"github.com/HauKuen/Annals/internal/utils/respcode"
+)
+
+func passwordArticle(t *testing.T, id uint, password string) *Article {
+	t.Helper()
+	hash, err := hashArticlePassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	article := &Article{Visibility: ArticleVisibilityPassword, PasswordHash: hash}
+	article.ID = id
+	return article
+}
+
+func setUnlockLimits(t *testing.T, client, article, delay int) {
+	t.Helper()
+	oldClient, oldArticle, oldDelay := utils.UnlockClientLimit, utils.UnlockArticleLimit, utils.UnlockArticleDelay
+	utils.UnlockClientLimit, utils.UnlockArticleLimit, utils.UnlockArticleDelay = client, article, delay
+	t.Cleanup(func() {
+		utils.UnlockClientLimit, utils.UnlockArticleLimit, utils.UnlockArticleDelay = oldClient, oldArticle, oldDelay
+	})
+}
+
+// TestUnlockArticleClientLimit 同一访客输错次数达到上限后，正确的密码也会被拒绝
+func TestUnlockArticleClientLimit(t *testing.T) {
+	setUnlockLimits(t, 3, 0, 0)
+	article := passwordArticle(t, 1001, "secret")
+
+	if code := UnlockArticle(article, "secret", "10.0.0.1"); code != respcode.SUCCESS {
+		t.Fatalf("correct password = %d, want SUCCESS", code)
+	}
+	for i := 0; i < 3; i++ {
+		if code := UnlockArticle(article, "wrong", "10.0.0.1"); code != respcode.ErrorArtPasswordWrong {
+			t.Fatalf("attempt %d = %d, want ErrorArtPasswordWrong", i, code)
+		}
+	}
+	if code := UnlockArticle(article, "secret", "10.0.0.1"); code != respcode.ErrorArtUnlockTooMany {
+		t.Fatalf("after limit = %d, want ErrorArtUnlockTooMany", code)
+	}
+	if code := UnlockArticle(article, "secret", "10.0.0.2"); code != respcode.SUCCESS {
+		t.Fatalf("other client = %d, want SUCCESS", code)
+	}
+}
+
+// TestUnlockArticleNoGlobalLockout 所有访客输错的次数超过上限后，知道密码的访客仍能解锁
+func TestUnlockArticleNoGlobalLockout(t *testing.T) {
+	setUnlockLimits(t, 2, 5, 0)
+	article := passwordArticle(t, 1002, "secret")
+
+	for i := 0; i < 10; i++ {
+		UnlockArticle(article, "wrong", fmt.Sprintf("10.1.0.%d", i))
+	}
+	if code := UnlockArticle(article, "secret", "10.1.1.1"); code != respcode.SUCCESS {
+		t.Fatalf("correct password after global failures = %d, want SUCCESS", code)
+	}
+}
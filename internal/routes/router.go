@@ -5,20 +5,37 @@ import (
 
 	"github.com/HauKuen/Annals/internal/utils/respcode"
 
+	"github.com/HauKuen/Annals/internal/api/site"
 	v1 "github.com/HauKuen/Annals/internal/api/v1"
 	"github.com/HauKuen/Annals/internal/middleware"
 	"github.com/HauKuen/Annals/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func InitRouter() {
+// InitRouter 初始化路由，返回监听 server.http_port 的服务器，由调用方负责启动和关闭
+func InitRouter() *http.Server {
 	gin.SetMode(utils.AppMode)
 	router := gin.New()
+	// 默认信任所有代理，客户端可以伪造 X-Forwarded-For，只信任配置的代理
+	if err := router.SetTrustedProxies(utils.TrustedProxies); err != nil {
+		utils.Log.Fatal("信任代理配置无效:", err)
+	}
 
 	router.Use(cors())
 	router.Use(gin.Recovery())
 	router.Use(utils.LoggerMiddleware())
 
+	// 订阅源，文件名为 rss.xml 或 atom.xml
+	feed := router.Group("/feed")
+	{
+		feed.GET(":file", site.SiteFeed)
+		feed.GET("category/:id/:file", site.CategoryFeed)
+		feed.GET("user/:id/:file", site.AuthorFeed)
+	}
+
+	router.GET("/sitemap.xml", site.Sitemap)
+	router.GET("/robots.txt", site.Robots)
+
 	r := router.Group("/api/v1")
 	{
 		// 公开接口
@@ -28,6 +45,33 @@ func InitRouter() {
 			auth.GET("validate", v1.ValidateToken)
 		}
 
+		// 公开的只读接口，无需登录，只返回已发布的内容
+		public := r.Group("/public")
+		{
+			public.GET("articles", v1.GetArticles)
+			public.GET("article/:id", v1.GetArticle)
+			public.GET("article/slug/:slug", v1.GetArticleBySlug)
+			public.POST("article/:id/unlock", v1.UnlockArticle)
+			public.GET("articles/search", v1.SearchArticles)
+			public.GET("articles/featured", v1.GetFeaturedArticles)
+			public.GET("categories", v1.GetCategories)
+			public.GET("category/:id", v1.GetCategory)
+			public.GET("category/slug/:slug", v1.GetCategoryBySlug)
+			public.GET("category/:id/articles", v1.GetCategoryArticles)
+			public.GET("tags", v1.GetTags)
+			public.GET("tags/cloud", v1.GetTagCloud)
+			public.GET("tag/:id/articles", v1.GetTagArticles)
+			public.GET("user/:id", v1.GetAuthorProfile)
+			public.GET("user/:id/articles", v1.GetUserArticles)
+			public.GET("article/:id/comments", v1.GetArticleComments)
+			public.GET("article/:id/related", v1.GetRelatedArticles)
+			public.GET("series", v1.GetSeriesList)
+			public.GET("series/:id", v1.GetSeries)
+			public.GET("archives", v1.GetArchives)
+			public.GET("archives/:year", v1.GetArchiveArticles)
+			public.GET("archives/:year/:month", v1.GetArchiveArticles)
+		}
+
 		// 需要认证的接口
 		auth = r.Group("/")
 		auth.Use(middleware.JWTAuth())
@@ -43,23 +87,88 @@ func InitRouter() {
 			// 分类相关接口
 			auth.POST("category/add", v1.AddCategory)
 			auth.GET("category/:id", v1.GetCategory)
+			auth.GET("category/slug/:slug", v1.GetCategoryBySlug)
 			auth.DELETE("category/delete/:id", v1.DeleteCategory)
 			auth.GET("categories", v1.GetCategories)
+			auth.PUT("category/move/:id", v1.MoveCategory)
+			auth.PUT("category/edit/:id", v1.EditCategory)
+			auth.PUT("category/merge/:id", v1.MergeCategory)
+
+			// 标签相关接口
+			auth.POST("tag/add", v1.AddTag)
+			auth.GET("tag/:id", v1.GetTag)
+			auth.PUT("tag/edit/:id", AdminRequired(), v1.EditTag)
+			auth.DELETE("tag/delete/:id", AdminRequired(), v1.DeleteTag)
+			auth.GET("tags", v1.GetTags)
+			auth.GET("tags/cloud", v1.GetTagCloud)
+			auth.GET("tag/:id/articles", v1.GetTagArticles)
 
 			// 文章相关接口
 			auth.GET("articles", v1.GetArticles)
 			auth.GET("article/:id", v1.GetArticle)
+			auth.GET("article/slug/:slug", v1.GetArticleBySlug)
+			auth.GET("article/:id/related", v1.GetRelatedArticles)
+			auth.POST("article/:id/unlock", v1.UnlockArticle)
 			auth.POST("article/add", v1.AddArticle)
 			auth.PUT("article/edit/:id", v1.EditArticle)
 			auth.DELETE("article/delete/:id", v1.DeleteArticle)
+			auth.PUT("article/publish/:id", AdminRequired(), v1.PublishArticle)
+			auth.PUT("article/unpublish/:id", v1.UnpublishArticle)
+			auth.PUT("article/submit/:id", v1.SubmitArticle)
+			auth.PUT("article/archive/:id", v1.ArchiveArticle)
+			auth.PUT("article/schedule/:id", AdminRequired(), v1.ScheduleArticle)
+			auth.GET("article/:id/revisions", v1.GetArticleRevisions)
+			auth.GET("article/:id/revisions/diff", v1.DiffArticleRevisions)
+			auth.GET("article/:id/revisions/:version", v1.GetArticleRevision)
+			auth.POST("article/:id/revisions/:version/restore", v1.RestoreArticleRevision)
 			auth.GET("category/:id/articles", v1.GetCategoryArticles)
 			auth.GET("user/:id/articles", v1.GetUserArticles)
 			auth.GET("articles/search", v1.SearchArticles)
+			auth.GET("articles/featured", v1.GetFeaturedArticles)
+			auth.PUT("article/pin/:id", AdminRequired(), v1.PinArticle)
+			auth.PUT("article/feature/:id", AdminRequired(), v1.FeatureArticle)
+			auth.GET("archives", v1.GetArchives)
+			auth.GET("archives/:year", v1.GetArchiveArticles)
+			auth.GET("archives/:year/:month", v1.GetArchiveArticles)
+
+			// 系列相关接口
+			auth.POST("series/add", v1.AddSeries)
+			auth.GET("series", v1.GetSeriesList)
+			auth.GET("series/:id", v1.GetSeries)
+			auth.PUT("series/edit/:id", v1.EditSeries)
+			auth.DELETE("series/delete/:id", v1.DeleteSeries)
+			auth.PUT("series/:id/articles", v1.SetSeriesArticles)
+
+			// 评论相关接口
+			auth.POST("comment/add", v1.AddComment)
+			auth.PUT("comment/edit/:id", v1.EditComment)
+			auth.DELETE("comment/delete/:id", v1.DeleteComment)
+			auth.GET("article/:id/comments", v1.GetArticleComments)
+
+			// 点赞和收藏接口
+			auth.PUT("article/:id/like", v1.LikeArticle)
+			auth.DELETE("article/:id/like", v1.UnlikeArticle)
+			auth.PUT("article/:id/bookmark", v1.BookmarkArticle)
+			auth.DELETE("article/:id/bookmark", v1.UnbookmarkArticle)
+			auth.GET("my/bookmarks", v1.GetMyBookmarks)
+
+			// 访问统计接口
+			auth.GET("article/:id/stats/views", v1.GetArticleViewTimeline)
+			auth.GET("article/:id/stats/referrers", v1.GetArticleReferrers)
+
+			// 管理接口
+			auth.POST("admin/search/rebuild", AdminRequired(), v1.RebuildSearchIndex)
+			auth.GET("admin/stats", AdminRequired(), v1.GetDashboardStats)
+			auth.GET("admin/comments", AdminRequired(), v1.GetModerationQueue)
+			auth.PUT("admin/comments/approve", AdminRequired(), v1.ApproveComments)
+			auth.PUT("admin/comments/reject", AdminRequired(), v1.RejectComments)
+			auth.PUT("admin/comments/spam", AdminRequired(), v1.MarkCommentsSpam)
 		}
 	}
 
-	if err := router.Run(utils.HttpPort); err != nil {
-		utils.Log.Fatal("服务器启动失败:", err)
+	return &http.Server{
+		Addr:    utils.HttpPort,
+		Handler: router,
 	}
 }
 
@@ -0,0 +1,184 @@
+package diff
+
+import "strings"
+
+// 行的变更类型
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Line 表示差异结果中的一行
+type Line struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"` // 在旧文本中的行号，从 1 开始
+	NewLine int    `json:"new_line,omitempty"` // 在新文本中的行号，从 1 开始
+}
+
+// Lines 使用 Myers 算法计算两段文本的行级差异
+func Lines(oldText, newText string) []Line {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	// 公共前缀和后缀不参与计算，减少算法的搜索空间
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, Line{Op: OpEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+
+	middle := myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, l := range middle {
+		if l.OldLine > 0 {
+			l.OldLine += prefix
+		}
+		if l.NewLine > 0 {
+			l.NewLine += prefix
+		}
+		lines = append(lines, l)
+	}
+
+	for i := 0; i < suffix; i++ {
+		oldIdx := len(a) - suffix + i
+		newIdx := len(b) - suffix + i
+		lines = append(lines, Line{Op: OpEqual, Text: a[oldIdx], OldLine: oldIdx + 1, NewLine: newIdx + 1})
+	}
+
+	return lines
+}
+
+// myers 计算最短编辑脚本
+//
+// 使用 Myers 算法的线性空间版本：找到最短编辑路径中间的蛇形段后递归处理两侧，
+// 内存占用为 O(n+m)。
+func myers(a, b []string) []Line {
+	d := &differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	return d.lines
+}
+
+type differ struct {
+	a, b  []string
+	lines []Line
+}
+
+// compare 比较 a[a0:a1] 和 b[b0:b1]，结果按顺序追加到 lines
+func (d *differ) compare(a0, a1, b0, b1 int) {
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.equal(a0, b0)
+		a0++
+		b0++
+	}
+	suffix := 0
+	for a0 < a1 && b0 < b1 && d.a[a1-1] == d.b[b1-1] {
+		a1--
+		b1--
+		suffix++
+	}
+
+	switch {
+	case a0 == a1:
+		for j := b0; j < b1; j++ {
+			d.lines = append(d.lines, Line{Op: OpInsert, Text: d.b[j], NewLine: j + 1})
+		}
+	case b0 == b1:
+		for i := a0; i < a1; i++ {
+			d.lines = append(d.lines, Line{Op: OpDelete, Text: d.a[i], OldLine: i + 1})
+		}
+	default:
+		// 两端都不相同且都不为空时编辑距离至少为 2，两侧的子问题都严格更小
+		x, y, u, v := d.middleSnake(a0, a1, b0, b1)
+		d.compare(a0, x, b0, y)
+		for i, j := x, y; i < u; i, j = i+1, j+1 {
+			d.equal(i, j)
+		}
+		d.compare(u, a1, v, b1)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.equal(a1+i, b1+i)
+	}
+}
+
+func (d *differ) equal(i, j int) {
+	d.lines = append(d.lines, Line{Op: OpEqual, Text: d.a[i], OldLine: i + 1, NewLine: j + 1})
+}
+
+// middleSnake 从两端同时搜索，返回最短编辑路径中间蛇形段的起点 (x, y) 和终点 (u, v)
+//
+// vf[k] 保存正向搜索在对角线 k 上到达的最远 x，vb[k] 保存反向搜索在对角线 k 上
+// 距离终点的最远 x；两者在同一条对角线上相遇时即找到中间段。
+func (d *differ) middleSnake(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	vf := make([]int, 2*offset+1)
+	vb := make([]int, 2*offset+1)
+
+	for step := 0; step <= maxD; step++ {
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || (k != step && vf[offset+k-1] < vf[offset+k+1]) {
+				px = vf[offset+k+1]
+			} else {
+				px = vf[offset+k-1] + 1
+			}
+			py := px - k
+			sx, sy := px, py
+			for px < n && py < m && d.a[a0+px] == d.b[b0+py] {
+				px++
+				py++
+			}
+			vf[offset+k] = px
+
+			if odd && delta-k >= -(step-1) && delta-k <= step-1 && px+vb[offset+delta-k] >= n {
+				return a0 + sx, b0 + sy, a0 + px, b0 + py
+			}
+		}
+
+		for k := -step; k <= step; k += 2 {
+			var px int
+			if k == -step || (k != step && vb[offset+k-1] < vb[offset+k+1]) {
+				px = vb[offset+k+1]
+			} else {
+				px = vb[offset+k-1] + 1
+			}
+			py := px - k
+			sx, sy := px, py
+			for px < n && py < m && d.a[a1-1-px] == d.b[b1-1-py] {
+				px++
+				py++
+			}
+			vb[offset+k] = px
+
+			if !odd && delta-k >= -step && delta-k <= step && px+vf[offset+delta-k] >= n {
+				return a1 - px, b1 - py, a1 - sx, b1 - sy
+			}
+		}
+	}
+
+	// 不会到达：编辑距离不超过 n+m，搜索一定会在 maxD 轮内相遇
+	return a0, b0, a0, b0
+}
+
+// splitLines 按行切分文本，统一处理 \r\n 换行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		ops      string // 每行的变更类型：= 不变，+ 插入，- 删除
+	}{
+		{"identical", "a\nb\nc", "a\nb\nc", "==="},
+		{"both empty", "", "", ""},
+		{"old empty", "", "a\nb", "++"},
+		{"new empty", "a\nb", "", "--"},
+		{"insert middle", "a\nc", "a\nb\nc", "=+="},
+		{"insert front", "b\nc", "a\nb\nc", "+=="},
+		{"insert end", "a\nb", "a\nb\nc", "==+"},
+		{"delete middle", "a\nb\nc", "a\nc", "=-="},
+		{"replace line", "a\nb\nc", "a\nx\nc", "=-+="},
+		{"replace all", "a\nb", "x\ny", "--++"},
+		{"crlf", "a\r\nb\r\n", "a\nb\n", "=="},
+		{"interleaved", "a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := Lines(tt.old, tt.new)
+			if tt.ops != "" || len(lines) == 0 {
+				if got := opString(lines); got != tt.ops {
+					t.Errorf("ops = %q, want %q", got, tt.ops)
+				}
+			}
+			checkApply(t, tt.old, tt.new, lines)
+		})
+	}
+}
+
+// TestLinesMinimal 与动态规划求出的最长公共子序列比较，检查编辑脚本最短
+func TestLinesMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		a := randomLines(r, r.Intn(12))
+		b := randomLines(r, r.Intn(12))
+		oldText, newText := strings.Join(a, "\n"), strings.Join(b, "\n")
+
+		lines := Lines(oldText, newText)
+		checkApply(t, oldText, newText, lines)
+
+		edits := 0
+		for _, l := range lines {
+			if l.Op != OpEqual {
+				edits++
+			}
+		}
+		if want := len(a) + len(b) - 2*lcs(a, b); edits != want {
+			t.Fatalf("Lines(%q, %q) has %d edits, want %d", oldText, newText, edits, want)
+		}
+	}
+}
+
+// TestLinesLarge 完全改写的大段文本也能在线性空间内完成
+func TestLinesLarge(t *testing.T) {
+	a := make([]string, 10000)
+	b := make([]string, 10000)
+	for i := range a {
+		a[i] = "old " + strings.Repeat("x", i%7)
+		b[i] = "new " + strings.Repeat("y", i%5)
+	}
+	oldText, newText := strings.Join(a, "\n"), strings.Join(b, "\n")
+	checkApply(t, oldText, newText, Lines(oldText, newText))
+}
+
+// checkApply 检查差异结果的行号连续，且按结果还原的旧文本和新文本与输入一致
+func checkApply(t *testing.T, oldText, newText string, lines []Line) {
+	t.Helper()
+	var oldLines, newLines []string
+	for _, l := range lines {
+		switch l.Op {
+		case OpEqual:
+			oldLines = append(oldLines, l.Text)
+			newLines = append(newLines, l.Text)
+			if l.OldLine != len(oldLines) || l.NewLine != len(newLines) {
+				t.Fatalf("equal line %+v has wrong line numbers", l)
+			}
+		case OpDelete:
+			oldLines = append(oldLines, l.Text)
+			if l.OldLine != len(oldLines) || l.NewLine != 0 {
+				t.Fatalf("deleted line %+v has wrong line numbers", l)
+			}
+		case OpInsert:
+			newLines = append(newLines, l.Text)
+			if l.NewLine != len(newLines) || l.OldLine != 0 {
+				t.Fatalf("inserted line %+v has wrong line numbers", l)
+			}
+		}
+	}
+	if got, want := oldLines, splitLines(oldText); strings.Join(got, "\n") != strings.Join(want, "\n") || len(got) != len(want) {
+		t.Fatalf("old text = %q, want %q", got, want)
+	}
+	if got, want := newLines, splitLines(newText); strings.Join(got, "\n") != strings.Join(want, "\n") || len(got) != len(want) {
+		t.Fatalf("new text = %q, want %q", got, want)
+	}
+}
+
+func opString(lines []Line) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		switch l.Op {
+		case OpEqual:
+			sb.WriteByte('=')
+		case OpInsert:
+			sb.WriteByte('+')
+		case OpDelete:
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
+func randomLines(r *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(rune('a' + r.Intn(3)))
+	}
+	return lines
+}
+
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
@@ -0,0 +1,145 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Page 分页参数
+//
+// 游标模式下按 (created_at, id) 做键集分页，不统计总数，查询后由 Paginate 填充 NextCursor 和 PrevCursor；
+// 否则按 Size 和 Num 做页码分页。
+type Page struct {
+	Size int
+	Num  int
+
+	UseCursor bool
+	Cursor    *Cursor // 为空时返回第一页
+	Asc       bool    // 游标模式下按创建时间升序排列，默认降序
+
+	NextCursor string
+	PrevCursor string
+}
+
+// Cursor 游标指向的记录，Prev 为 true 时表示向前翻页
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"i"`
+	Prev      bool      `json:"p,omitempty"`
+}
+
+// ErrCursorInvalid 游标无法解析
+var ErrCursorInvalid = errors.New("invalid cursor")
+
+// NewPage 创建页码分页参数，页码和每页数量无效时使用默认值
+func NewPage(size int, num int) *Page {
+	if size <= 0 {
+		size = 10
+	}
+	if num <= 0 {
+		num = 1
+	}
+	return &Page{Size: size, Num: num}
+}
+
+// NewCursorPage 创建游标分页参数，cursor 为空字符串时返回第一页
+func NewCursorPage(size int, cursor string) (*Page, error) {
+	page := NewPage(size, 1)
+	page.UseCursor = true
+	if cursor == "" {
+		return page, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 {
+		return nil, ErrCursorInvalid
+	}
+	page.Cursor = &c
+	return page, nil
+}
+
+// EncodeCursor 将游标编码为不透明的字符串
+func EncodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// IsFirst 当前页是否为第一页，游标模式下需在 Paginate 之后调用
+func (p *Page) IsFirst() bool {
+	if !p.UseCursor {
+		return p.Num == 1
+	}
+	return p.Cursor == nil || (p.Cursor.Prev && p.PrevCursor == "")
+}
+
+// Scope 返回应用排序和分页的 scope，createdAt 和 id 为游标比较和排序所用的列
+//
+// 页码分页时使用 order 排序；游标分页时忽略 order，按 createdAt 和 id 排序，
+// 并多取一条记录用于判断是否还有下一页，查询结果需交给 Paginate 处理。
+func (p *Page) Scope(createdAt string, id string, order ...func(*gorm.DB) *gorm.DB) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if !p.UseCursor {
+			return tx.Scopes(order...).Limit(p.Size).Offset((p.Num - 1) * p.Size)
+		}
+
+		// 向前翻页时反向查询，取出后再调整回展示顺序
+		asc := p.Asc
+		if p.Cursor != nil && p.Cursor.Prev {
+			asc = !asc
+		}
+		op, desc := "<", true
+		if asc {
+			op, desc = ">", false
+		}
+
+		if p.Cursor != nil {
+			tx = tx.Where("("+createdAt+" "+op+" ? OR ("+createdAt+" = ? AND "+id+" "+op+" ?))",
+				p.Cursor.CreatedAt, p.Cursor.CreatedAt, p.Cursor.ID)
+		}
+		return tx.Order(clause.OrderByColumn{Column: clause.Column{Name: createdAt, Raw: true}, Desc: desc}).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: id, Raw: true}, Desc: desc}).
+			Limit(p.Size + 1)
+	}
+}
+
+// Paginate 处理游标模式的查询结果：去掉多取的记录、恢复展示顺序并填充前后页游标，
+// key 返回记录的创建时间和ID
+func Paginate[T any](p *Page, rows []T, key func(T) (time.Time, uint)) []T {
+	if !p.UseCursor {
+		return rows
+	}
+
+	more := len(rows) > p.Size
+	if more {
+		rows = rows[:p.Size]
+	}
+	prev := p.Cursor != nil && p.Cursor.Prev
+	if prev {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows
+	}
+
+	// 向前翻页时后面一定还有记录；向后翻页时只要不是第一页，前面一定还有记录
+	if more || prev {
+		t, id := key(rows[len(rows)-1])
+		p.NextCursor = EncodeCursor(Cursor{CreatedAt: t, ID: id})
+	}
+	if (more && prev) || (!prev && p.Cursor != nil) {
+		t, id := key(rows[0])
+		p.PrevCursor = EncodeCursor(Cursor{CreatedAt: t, ID: id, Prev: true})
+	}
+	return rows
+}
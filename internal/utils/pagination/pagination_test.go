@@ -0,0 +1,235 @@
+package pagination
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type row struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+func rowKey(r row) (time.Time, uint) {
+	return r.CreatedAt, r.ID
+}
+
+// testRows 生成 n 条记录，每两条记录的创建时间相同，用于检查按ID区分
+func testRows(n int) []row {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]row, n)
+	for i := range rows {
+		rows[i] = row{CreatedAt: base.Add(time.Duration(i/2) * time.Minute), ID: uint(i + 1)}
+	}
+	return rows
+}
+
+// fetch 在内存中模拟 Scope 生成的查询
+func fetch(p *Page, all []row) []row {
+	asc := p.Asc
+	if p.Cursor != nil && p.Cursor.Prev {
+		asc = !asc
+	}
+	less := func(a, b row) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+
+	var rows []row
+	for _, r := range all {
+		c := p.Cursor
+		if c == nil || (asc && less(row{c.CreatedAt, c.ID}, r)) || (!asc && less(r, row{c.CreatedAt, c.ID})) {
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if asc {
+			return less(rows[i], rows[j])
+		}
+		return less(rows[j], rows[i])
+	})
+	if len(rows) > p.Size+1 {
+		rows = rows[:p.Size+1]
+	}
+	return rows
+}
+
+func mustPage(t *testing.T, size int, cursor string) *Page {
+	t.Helper()
+	p, err := NewCursorPage(size, cursor)
+	if err != nil {
+		t.Fatalf("NewCursorPage(%q): %v", cursor, err)
+	}
+	return p
+}
+
+func ids(rows []row) []uint {
+	result := make([]uint, len(rows))
+	for i, r := range rows {
+		result[i] = r.ID
+	}
+	return result
+}
+
+func TestPaginateRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		total int
+		size  int
+		asc   bool
+	}{
+		{"desc", 23, 5, false},
+		{"asc", 23, 5, true},
+		{"exact pages", 20, 5, false},
+		{"single page", 3, 5, false},
+		{"one per page", 4, 1, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			all := testRows(tt.total)
+
+			// 向后翻到最后一页
+			var pages [][]uint
+			var prevCursors []string
+			cursor := ""
+			for {
+				p := mustPage(t, tt.size, cursor)
+				p.Asc = tt.asc
+				rows := Paginate(p, fetch(p, all), rowKey)
+				if len(pages) == 0 != p.IsFirst() {
+					t.Fatalf("page %d: IsFirst = %v", len(pages), p.IsFirst())
+				}
+				if len(pages) == 0 != (p.PrevCursor == "") {
+					t.Fatalf("page %d: PrevCursor = %q", len(pages), p.PrevCursor)
+				}
+				pages = append(pages, ids(rows))
+				prevCursors = append(prevCursors, p.PrevCursor)
+				if p.NextCursor == "" {
+					break
+				}
+				cursor = p.NextCursor
+			}
+
+			var got []uint
+			for _, page := range pages {
+				got = append(got, page...)
+			}
+			want := ids(fetch(&Page{Size: tt.total, Asc: tt.asc}, all))
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("forward pages = %v, want %v", pages, want)
+			}
+			if last := pages[len(pages)-1]; len(last) == 0 || len(last) > tt.size {
+				t.Fatalf("last page = %v", last)
+			}
+
+			// 从最后一页向前翻回第一页
+			cursor = prevCursors[len(prevCursors)-1]
+			for i := len(pages) - 2; i >= 0; i-- {
+				p := mustPage(t, tt.size, cursor)
+				p.Asc = tt.asc
+				rows := Paginate(p, fetch(p, all), rowKey)
+				if !reflect.DeepEqual(ids(rows), pages[i]) {
+					t.Fatalf("back to page %d = %v, want %v", i, ids(rows), pages[i])
+				}
+				if p.NextCursor == "" {
+					t.Fatalf("back to page %d: missing NextCursor", i)
+				}
+				if i == 0 != p.IsFirst() {
+					t.Fatalf("back to page %d: IsFirst = %v", i, p.IsFirst())
+				}
+				if i == 0 {
+					if p.PrevCursor != "" {
+						t.Fatalf("first page has PrevCursor %q", p.PrevCursor)
+					}
+					break
+				}
+				cursor = p.PrevCursor
+			}
+		})
+	}
+}
+
+func TestPaginateEmpty(t *testing.T) {
+	p := mustPage(t, 5, "")
+	if rows := Paginate(p, fetch(p, nil), rowKey); len(rows) != 0 {
+		t.Fatalf("rows = %v, want empty", rows)
+	}
+	if p.NextCursor != "" || p.PrevCursor != "" || !p.IsFirst() {
+		t.Fatalf("empty page = %+v", p)
+	}
+}
+
+func TestPaginateOffset(t *testing.T) {
+	p := NewPage(5, 2)
+	rows := testRows(3)
+	if got := Paginate(p, rows, rowKey); !reflect.DeepEqual(got, rows) {
+		t.Fatalf("offset rows = %v, want unchanged", got)
+	}
+	if p.IsFirst() || p.NextCursor != "" || p.PrevCursor != "" {
+		t.Fatalf("offset page = %+v", p)
+	}
+}
+
+func TestNewCursorPageInvalid(t *testing.T) {
+	for _, cursor := range []string{"!!!", EncodeCursor(Cursor{})[:3], "e30"} {
+		if _, err := NewCursorPage(10, cursor); err != ErrCursorInvalid {
+			t.Errorf("NewCursorPage(%q) error = %v, want ErrCursorInvalid", cursor, err)
+		}
+	}
+
+	c := Cursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: 7, Prev: true}
+	p := mustPage(t, 0, EncodeCursor(c))
+	if p.Size != 10 || !reflect.DeepEqual(*p.Cursor, c) {
+		t.Fatalf("decoded page = %+v, cursor = %+v", p, p.Cursor)
+	}
+}
+
+func TestScope(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	byTitle := func(tx *gorm.DB) *gorm.DB { return tx.Order("title") }
+
+	tests := []struct {
+		name string
+		page *Page
+		want string
+		vars []interface{}
+	}{
+		{"offset", NewPage(10, 3), "ORDER BY title LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{"first", &Page{Size: 10, UseCursor: true},
+			"ORDER BY created_at DESC,id DESC LIMIT ?", []interface{}{11}},
+		{"next", &Page{Size: 10, UseCursor: true, Cursor: &Cursor{CreatedAt: at, ID: 5}},
+			"WHERE (created_at < ? OR (created_at = ? AND id < ?)) ORDER BY created_at DESC,id DESC LIMIT ?",
+			[]interface{}{at, at, uint(5), 11}},
+		{"prev", &Page{Size: 10, UseCursor: true, Cursor: &Cursor{CreatedAt: at, ID: 5, Prev: true}},
+			"WHERE (created_at > ? OR (created_at = ? AND id > ?)) ORDER BY created_at,id LIMIT ?",
+			[]interface{}{at, at, uint(5), 11}},
+		{"asc next", &Page{Size: 10, UseCursor: true, Asc: true, Cursor: &Cursor{CreatedAt: at, ID: 5}},
+			"WHERE (created_at > ? OR (created_at = ? AND id > ?)) ORDER BY created_at,id LIMIT ?",
+			[]interface{}{at, at, uint(5), 11}},
+		{"asc prev", &Page{Size: 10, UseCursor: true, Asc: true, Cursor: &Cursor{CreatedAt: at, ID: 5, Prev: true}},
+			"WHERE (created_at < ? OR (created_at = ? AND id < ?)) ORDER BY created_at DESC,id DESC LIMIT ?",
+			[]interface{}{at, at, uint(5), 11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := db.Table("t").Scopes(tt.page.Scope("created_at", "id", byTitle)).Find(&[]row{}).Statement
+			if sql := stmt.SQL.String(); !strings.HasSuffix(sql, tt.want) {
+				t.Fatalf("sql = %q, want suffix %q", sql, tt.want)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.vars) {
+				t.Fatalf("vars = %v, want %v", stmt.Vars, tt.vars)
+			}
+		})
+	}
+}
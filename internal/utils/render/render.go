@@ -0,0 +1,181 @@
+package render
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// 文章内容格式
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatPlain    = "plain"
+)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM, extension.Footnote),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	// 允许 Markdown 中内嵌 HTML，输出统一经过 sanitizer 过滤
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// sanitizer 在 UGC 策略基础上放行代码高亮、脚注、标题锚点和任务列表需要的属性
+var sanitizer = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^footnote(s|-ref|-backref)$`)).OnElements("a", "div")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\w:-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6", "li", "sup")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-[a-z]+$`)).OnElements("a", "div", "sup")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}()
+
+var stripper = bluemonday.StrictPolicy()
+
+var (
+	blankLines = regexp.MustCompile(`\n\s*\n`)
+	lineBreaks = regexp.MustCompile(`\s*\n\s*`)
+)
+
+// blockTags 块级标签，提取纯文本时在其前插入换行，避免相邻段落的文字粘连
+var blockTags = regexp.MustCompile(`(?i)<(/?(p|div|li|h[1-6]|tr|td|th|pre|blockquote)|br)\b`)
+
+// ValidFormat 检查内容格式是否受支持
+func ValidFormat(format string) bool {
+	return format == FormatMarkdown || format == FormatHTML || format == FormatPlain
+}
+
+// HTML 将指定格式的内容渲染为过滤后的安全 HTML
+func HTML(content string, format string) (string, error) {
+	switch format {
+	case FormatHTML:
+		return sanitizer.Sanitize(content), nil
+	case FormatPlain:
+		return plainToHTML(content), nil
+	default:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(content), &buf); err != nil {
+			return "", err
+		}
+		return sanitizer.Sanitize(buf.String()), nil
+	}
+}
+
+// PlainText 去除 HTML 标签，返回按块分行的纯文本
+func PlainText(htmlContent string) string {
+	htmlContent = blockTags.ReplaceAllString(htmlContent, "\n$0")
+	text := html.UnescapeString(stripper.Sanitize(htmlContent))
+	return strings.TrimSpace(lineBreaks.ReplaceAllString(text, "\n"))
+}
+
+// plainToHTML 将纯文本按空行分段，段内换行转为 <br>
+func plainToHTML(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, paragraph := range blankLines.Split(strings.TrimSpace(content), -1) {
+		if paragraph == "" {
+			continue
+		}
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		b.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return b.String()
+}
+
+// Heading 目录中的一个标题
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+var (
+	headingTag = regexp.MustCompile(`(?is)<h([1-6])([^>]*)>(.*?)</h[1-6]>`)
+	idAttr     = regexp.MustCompile(`\bid="([^"]*)"`)
+)
+
+// TOC 从渲染后的 HTML 中提取标题作为目录
+func TOC(htmlContent string) []Heading {
+	var headings []Heading
+	for _, m := range headingTag.FindAllStringSubmatch(htmlContent, -1) {
+		text := PlainText(m[3])
+		if text == "" {
+			continue
+		}
+
+		heading := Heading{Level: int(m[1][0] - '0'), Text: text}
+		if id := idAttr.FindStringSubmatch(m[2]); id != nil {
+			heading.ID = html.UnescapeString(id[1])
+		}
+		headings = append(headings, heading)
+	}
+	return headings
+}
+
+// 阅读速度，中日韩文字按字计，其他文字按词计
+const (
+	cjkCharsPerMinute = 300
+	wordsPerMinute    = 200
+)
+
+// ReadingStats 统计纯文本的字数并估算阅读时间（分钟），中日韩文字每个字计为一个词
+func ReadingStats(text string) (wordCount int, minutes int) {
+	cjk, words := 0, 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+
+	wordCount = cjk + words
+	if wordCount == 0 {
+		return 0, 0
+	}
+
+	seconds := cjk*60/cjkCharsPerMinute + words*60/wordsPerMinute
+	minutes = (seconds + 59) / 60
+	if minutes < 1 {
+		minutes = 1
+	}
+	return wordCount, minutes
+}
+
+// Excerpt 截取纯文本的开头作为摘要，最多 maxRunes 个字符
+func Excerpt(text string, maxRunes int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
@@ -34,44 +34,125 @@ const (
 	ErrorCateNotExist  = 3002
 	ErrorEmptyCateName = 3003
 
+	ErrorCateParentNotExist = 3004
+	ErrorCateCycle          = 3005
+	ErrorCateHasArticles    = 3006
+	ErrorCateMergeSelf      = 3007
+
 	ArticleError       = 4000
 	ErrorArtNotExist   = 4001
 	ErrorArtTitleEmpty = 4002
 	ErrorArtContent    = 4003
 
+	ErrorArtStatusInvalid     = 4004
+	ErrorArtStatusTransition  = 4005
+	ErrorArtScheduleInvalid   = 4006
+	ErrorRevisionNotExist     = 4007
+	ErrorArtFormatInvalid     = 4008
+	ErrorArchiveDateInvalid   = 4009
+	ErrorArtPromotionExpiry   = 4010
+	ErrorArtSortInvalid       = 4011
+	ErrorArtFilterInvalid     = 4012
+	ErrorArtVisibilityInvalid = 4013
+	ErrorArtPasswordEmpty     = 4014
+	ErrorArtPasswordWrong     = 4015
+	ErrorArtUnlockTooMany     = 4016
+
+	TagError          = 5000
+	ErrorTagNameUsed  = 5001
+	ErrorTagNotExist  = 5002
+	ErrorEmptyTagName = 5003
+
+	SlugError        = 6000
+	ErrorSlugInvalid = 6001
+	ErrorSlugUsed    = 6002
+
+	CommentError              = 7000
+	ErrorCommentNotExist      = 7001
+	ErrorCommentEmpty         = 7002
+	ErrorCommentParentInvalid = 7003
+	ErrorCommentRejected      = 7004
+	ErrorCommentStatusInvalid = 7005
+
+	SeriesError               = 8000
+	ErrorSeriesNotExist       = 8001
+	ErrorSeriesTitleEmpty     = 8002
+	ErrorSeriesArticleInvalid = 8003
+
+	PageError          = 9000
+	ErrorCursorInvalid = 9001
+	ErrorCursorSort    = 9002
+
 	ErrorPasswordTooShort = 1010
 )
 
 var codeMsg = map[int]string{
-	SUCCESS:               "操作成功",
-	ERROR:                 "服务器错误",
-	BadRequest:            "Bad Request",
-	Unauthorized:          "Unauthorized",
-	Forbidden:             "Forbidden",
-	NotFound:              "Not Found",
-	MethodNotAllowed:      "Method Not Allowed",
-	UserError:             "User Error",
-	ErrorUsernameUsed:     "用户名已存在！",
-	ErrorPasswordWrong:    "密码错误",
-	ErrorUserNotExist:     "用户不存在",
-	ErrorUserInactive:     "用户账号已停用",
-	ErrorEmailUsed:        "邮箱已被使用",
-	ErrorInvalidEmail:     "无效的邮箱地址",
-	ErrorInvalidRole:      "无效的用户权限",
-	ErrorEmptyDisplayName: "显示名称不能为空",
-	ErrorInvalidAvatarURL: "无效的头像URL",
-	AuthError:             "认证错误",
-	ErrorTokenInvalid:     "无效的认证令牌",
-	ErrorNoPermission:     "没有操作权限",
-	CategoryError:         "分类错误",
-	ErrorCateNameUsed:     "该分类已存在",
-	ErrorCateNotExist:     "该分类不存在",
-	ErrorEmptyCateName:    "分类名称不能为空",
-	ArticleError:          "文章错误",
-	ErrorArtNotExist:      "文章不存在",
-	ErrorArtTitleEmpty:    "文章标题不能为空",
-	ErrorArtContent:       "文章内容不能为空",
-	ErrorPasswordTooShort: "密码长度太短",
+	SUCCESS:                   "操作成功",
+	ERROR:                     "服务器错误",
+	BadRequest:                "Bad Request",
+	Unauthorized:              "Unauthorized",
+	Forbidden:                 "Forbidden",
+	NotFound:                  "Not Found",
+	MethodNotAllowed:          "Method Not Allowed",
+	UserError:                 "User Error",
+	ErrorUsernameUsed:         "用户名已存在！",
+	ErrorPasswordWrong:        "密码错误",
+	ErrorUserNotExist:         "用户不存在",
+	ErrorUserInactive:         "用户账号已停用",
+	ErrorEmailUsed:            "邮箱已被使用",
+	ErrorInvalidEmail:         "无效的邮箱地址",
+	ErrorInvalidRole:          "无效的用户权限",
+	ErrorEmptyDisplayName:     "显示名称不能为空",
+	ErrorInvalidAvatarURL:     "无效的头像URL",
+	AuthError:                 "认证错误",
+	ErrorTokenInvalid:         "无效的认证令牌",
+	ErrorNoPermission:         "没有操作权限",
+	CategoryError:             "分类错误",
+	ErrorCateNameUsed:         "该分类已存在",
+	ErrorCateNotExist:         "该分类不存在",
+	ErrorEmptyCateName:        "分类名称不能为空",
+	ErrorCateParentNotExist:   "父分类不存在",
+	ErrorCateCycle:            "不能将分类移动到自身或其子分类下",
+	ErrorCateHasArticles:      "该分类下仍有文章",
+	ErrorCateMergeSelf:        "不能将分类合并到自身",
+	ArticleError:              "文章错误",
+	ErrorArtNotExist:          "文章不存在",
+	ErrorArtTitleEmpty:        "文章标题不能为空",
+	ErrorArtContent:           "文章内容不能为空",
+	ErrorArtStatusInvalid:     "无效的文章状态",
+	ErrorArtStatusTransition:  "当前文章状态不允许该操作",
+	ErrorArtScheduleInvalid:   "定时发布时间无效",
+	ErrorRevisionNotExist:     "文章修订版本不存在",
+	ErrorArtFormatInvalid:     "不支持的文章内容格式",
+	ErrorArchiveDateInvalid:   "归档日期无效",
+	ErrorArtPromotionExpiry:   "过期时间必须晚于当前时间",
+	ErrorArtSortInvalid:       "不支持的排序字段或排序方向",
+	ErrorArtFilterInvalid:     "筛选条件无效",
+	ErrorArtVisibilityInvalid: "文章可见性无效",
+	ErrorArtPasswordEmpty:     "密码保护的文章必须设置访问密码",
+	ErrorArtPasswordWrong:     "文章访问密码错误",
+	ErrorArtUnlockTooMany:     "密码错误次数过多，请稍后再试",
+	TagError:                  "标签错误",
+	ErrorTagNameUsed:          "该标签已存在",
+	ErrorTagNotExist:          "该标签不存在",
+	ErrorEmptyTagName:         "标签名称不能为空",
+	SlugError:                 "Slug错误",
+	ErrorSlugInvalid:          "Slug只能包含小写字母、数字和连字符",
+	ErrorSlugUsed:             "该Slug已被使用",
+	CommentError:              "评论错误",
+	ErrorCommentNotExist:      "评论不存在",
+	ErrorCommentEmpty:         "评论内容不能为空",
+	ErrorCommentParentInvalid: "回复的评论不存在",
+	ErrorCommentRejected:      "评论被拒绝，请稍后再试",
+	ErrorCommentStatusInvalid: "评论状态无效",
+	SeriesError:               "系列错误",
+	ErrorSeriesNotExist:       "系列不存在",
+	ErrorSeriesTitleEmpty:     "系列标题不能为空",
+	ErrorSeriesArticleInvalid: "文章不存在、重复或不属于系列作者",
+	PageError:                 "分页错误",
+	ErrorCursorInvalid:        "分页游标无效",
+	ErrorCursorSort:           "游标分页只支持按创建时间排序",
+	ErrorPasswordTooShort:     "密码长度太短",
 }
 
 func GetErrMsg(code int) string {
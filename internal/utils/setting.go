@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -10,6 +11,8 @@ var (
 	AppMode  string
 	HttpPort string
 	JwtKey   string
+	// 信任的反向代理，只有来自这些地址的请求才会使用 X-Forwarded-For 中的客户端 IP
+	TrustedProxies []string
 
 	Host              string
 	Port              string
@@ -20,12 +23,47 @@ var (
 	DbMaxOpenConns    int
 	DbConnMaxLifetime int
 	DbEnableSqlLog    bool
+
+	SlugPinyin    bool
+	SlugFallback  string
+	SlugMaxLength int
+
+	SearchEngine string
+
+	CommentModeration      bool
+	CommentTrustedApproved int
+	CommentSpamChecker     string
+	CommentMaxLinks        int
+	CommentBlockedWords    []string
+	CommentDuplicateWindow int
+	CommentRateLimit       int
+	CommentRateWindow      int
+
+	ViewDedupeWindow  int
+	ViewFlushInterval int
+	ViewBotAgents     []string
+
+	UnlockClientLimit  int
+	UnlockArticleLimit int
+	UnlockArticleDelay int
+	UnlockWindow       int
+
+	StatsCacheTTL int
+
+	SiteTitle       string
+	SiteURL         string
+	SiteDescription string
+	SiteLanguage    string
+	SiteFeedSize    int
+	SiteSitemapTTL  int
+	SiteRobots      string
 )
 
 func LoadConfig() error {
 	viper.SetConfigName("config")
 	viper.SetConfigType("toml")
 	viper.AddConfigPath("config")
+	setDefaults()
 	err := viper.ReadInConfig()
 	if err != nil {
 		return fmt.Errorf("fatal error config file: %w", err)
@@ -34,6 +72,7 @@ func LoadConfig() error {
 	AppMode = viper.GetString("server.app_mode")
 	HttpPort = viper.GetString("server.http_port")
 	JwtKey = viper.GetString("server.jwt_key")
+	TrustedProxies = viper.GetStringSlice("server.trusted_proxies")
 	Host = viper.GetString("mysql.host")
 	Port = viper.GetString("mysql.port")
 	User = viper.GetString("mysql.user")
@@ -43,10 +82,70 @@ func LoadConfig() error {
 	DbMaxOpenConns = viper.GetInt("mysql.max_open_conns")
 	DbConnMaxLifetime = viper.GetInt("mysql.conn_max_lifetime")
 	DbEnableSqlLog = viper.GetBool("mysql.enable_sql_log")
+	SlugPinyin = viper.GetBool("slug.pinyin")
+	SlugFallback = viper.GetString("slug.fallback")
+	SlugMaxLength = viper.GetInt("slug.max_length")
+	SearchEngine = viper.GetString("search.engine")
+	CommentModeration = viper.GetBool("comment.moderation")
+	CommentTrustedApproved = viper.GetInt("comment.trusted_approved")
+	CommentSpamChecker = viper.GetString("comment.spam_checker")
+	CommentMaxLinks = viper.GetInt("comment.max_links")
+	CommentBlockedWords = viper.GetStringSlice("comment.blocked_words")
+	CommentDuplicateWindow = viper.GetInt("comment.duplicate_window")
+	CommentRateLimit = viper.GetInt("comment.rate_limit")
+	CommentRateWindow = viper.GetInt("comment.rate_window")
+	ViewDedupeWindow = viper.GetInt("view.dedupe_window")
+	ViewFlushInterval = viper.GetInt("view.flush_interval")
+	ViewBotAgents = viper.GetStringSlice("view.bot_agents")
+	UnlockClientLimit = viper.GetInt("unlock.client_limit")
+	UnlockArticleLimit = viper.GetInt("unlock.article_limit")
+	UnlockArticleDelay = viper.GetInt("unlock.article_delay")
+	UnlockWindow = viper.GetInt("unlock.window")
+	StatsCacheTTL = viper.GetInt("stats.cache_ttl")
+	SiteTitle = viper.GetString("site.title")
+	SiteURL = strings.TrimRight(viper.GetString("site.url"), "/")
+	SiteDescription = viper.GetString("site.description")
+	SiteLanguage = viper.GetString("site.language")
+	SiteFeedSize = viper.GetInt("site.feed_size")
+	SiteSitemapTTL = viper.GetInt("site.sitemap_cache_ttl")
+	SiteRobots = viper.GetString("site.robots")
 
 	return validateConfig()
 }
 
+// setDefaults 设置可选配置项的默认值
+func setDefaults() {
+	viper.SetDefault("server.trusted_proxies", []string{})
+	viper.SetDefault("slug.pinyin", true)
+	viper.SetDefault("slug.fallback", "post")
+	viper.SetDefault("slug.max_length", 80)
+	viper.SetDefault("search.engine", "mysql")
+	viper.SetDefault("comment.moderation", true)
+	viper.SetDefault("comment.trusted_approved", 3)
+	viper.SetDefault("comment.spam_checker", "heuristic")
+	viper.SetDefault("comment.max_links", 2)
+	viper.SetDefault("comment.blocked_words", []string{})
+	viper.SetDefault("comment.duplicate_window", 60)
+	viper.SetDefault("comment.rate_limit", 5)
+	viper.SetDefault("comment.rate_window", 10)
+	viper.SetDefault("view.dedupe_window", 30)
+	viper.SetDefault("view.flush_interval", 60)
+	viper.SetDefault("view.bot_agents", []string{
+		"bot", "crawl", "spider", "slurp", "curl", "wget", "python-requests",
+		"headless", "facebookexternalhit", "lighthouse",
+	})
+	viper.SetDefault("unlock.client_limit", 5)
+	viper.SetDefault("unlock.article_limit", 50)
+	viper.SetDefault("unlock.article_delay", 2)
+	viper.SetDefault("unlock.window", 15)
+	viper.SetDefault("stats.cache_ttl", 300)
+	viper.SetDefault("site.title", "Annals")
+	viper.SetDefault("site.url", "http://localhost:3000")
+	viper.SetDefault("site.language", "zh-CN")
+	viper.SetDefault("site.feed_size", 20)
+	viper.SetDefault("site.sitemap_cache_ttl", 600)
+}
+
 func validateConfig() error {
 	requiredConfigs := map[string]string{
 		"AppMode":  AppMode,
@@ -0,0 +1,63 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs 单个 sitemap 文件允许的最大 URL 数量
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL sitemap 中的一个页面，LastMod 为零值时省略
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type index struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet 生成包含给定页面的 sitemap
+func URLSet(urls []URL) ([]byte, error) {
+	return marshal(urlSet{XMLNS: namespace, URLs: entries(urls)})
+}
+
+// Index 生成 sitemap 索引，urls 为各个子 sitemap 的地址
+func Index(urls []URL) ([]byte, error) {
+	return marshal(index{XMLNS: namespace, Sitemaps: entries(urls)})
+}
+
+func entries(urls []URL) []entry {
+	result := make([]entry, len(urls))
+	for i, u := range urls {
+		result[i].Loc = u.Loc
+		if !u.LastMod.IsZero() {
+			result[i].LastMod = u.LastMod.Format(time.RFC3339)
+		}
+	}
+	return result
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,70 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// Options 控制 slug 的生成方式
+type Options struct {
+	Pinyin    bool // 是否将汉字转写为拼音
+	MaxLength int  // 最大长度，0 表示不限制
+}
+
+// Make 根据文本生成只包含小写字母、数字和连字符的 slug，无法生成时返回空字符串
+func Make(text string, opts Options) string {
+	var words []string
+	var word strings.Builder
+	var han []rune
+
+	flushWord := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	flushHan := func() {
+		if len(han) > 0 {
+			words = append(words, pinyin.LazyPinyin(string(han), pinyin.NewArgs())...)
+			han = han[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			flushHan()
+			word.WriteRune(unicode.ToLower(r))
+		case opts.Pinyin && unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		default:
+			// 其他字符作为分隔符
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+
+	result := strings.Join(words, "-")
+	if opts.MaxLength > 0 && len(result) > opts.MaxLength {
+		result = strings.TrimRight(result[:opts.MaxLength], "-")
+	}
+	return result
+}
+
+// Valid 检查 slug 是否只包含小写字母、数字和单个连字符
+func Valid(s string) bool {
+	if s == "" || s[0] == '-' || s[len(s)-1] == '-' || strings.Contains(s, "--") {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}